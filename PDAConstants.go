@@ -1,6 +0,0 @@
-package main
-
-const PDA_FILES_BASE_FOLDER string = "./PDAFiles"
-const PDA_FILE_NAME_PREFIX string = "testPdaSpecs"
-const PDA_FILE_NAME_POSTFIX string = ".json"
-const PDA_PENDING_QUEUE_LENGTH int = 100
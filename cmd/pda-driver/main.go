@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/pravin-gayal/pda-processor/pda"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+PDA driver, runs a PDA specification on an input token stream from the command line,
+
+	pda-driver <specification-file> [input-file]
+	pda-driver bench <specification-file> <input-file> [repeat]
+	pda-driver analyze <specification-file>
+	pda-driver grammar <grammar-file> [specification-file]
+	pda-driver optimize <specification-file> [optimized-file]
+	pda-driver graph <specification-file> [dot|mermaid] [input-file]
+*/
+func main() {
+	// verbosity and format of the logs, e.g. PDA_LOG_LEVEL=trace logs every transition evaluated
+	if err := pda.ConfigureLogging(os.Getenv("PDA_LOG_LEVEL"), os.Getenv("PDA_LOG_FORMAT")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if len(os.Args) < 2 {
+		fmt.Println("usage: pda-driver <specification-file> [input-file]")
+		os.Exit(1)
+	}
+
+	runDriver(os.Args[1:])
+}
+
+func runDriver(args []string) {
+	switch args[0] {
+	case "bench":
+		runBenchmark(args[1:])
+		return
+	case "analyze":
+		runDeterminismAnalysis(args[1:])
+		return
+	case "grammar":
+		runGrammarCompiler(args[1:])
+		return
+	case "optimize":
+		runOptimizer(args[1:])
+		return
+	case "graph":
+		runGraph(args[1:])
+		return
+	}
+
+	// get first argument as file path
+	pdaSpecsFilePath := args[0]
+
+	// load PDA with specification from file on given paths
+	pdaProcessor := &pda.PDAProcessor{}
+	opened, err := pdaProcessor.Open(pdaSpecsFilePath)
+	if err != nil {
+		log.Fatal(err)
+	} else if !opened {
+		log.Fatal("PDA specification file was not opened")
+	}
+
+	// initialize input token stream
+	inputString := ""
+
+	// check for optional input token stream
+	if len(args) == 2 {
+		inputFilePath := args[1]
+		// read input stream from the file
+		str, _ := readInputFromFile(inputFilePath)
+		inputString = str
+	} else {
+		fmt.Print("Input token stream file path is not specified, \nPlease enter input token stream: ")
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Scan()
+		inputString = scanner.Text()
+	}
+
+	// feed in inout token to PDA
+	transitionsTaken, output, err := pdaProcessor.EvaluateInput(inputString)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// check if PDA accepted the input token
+	acceptedToken := pdaProcessor.IsAccepted()
+	printFinalStatus(pdaProcessor, inputString, acceptedToken, transitionsTaken, output)
+
+	pdaProcessor.Reset()
+	pdaProcessor.Close()
+
+	fmt.Println("End of main")
+	fmt.Println("PDA has finished executing the given input token stream")
+	fmt.Println("***************************************************************")
+}
+
+/**
+measure the throughput of put for the token stream in input file, the stream is evaluated repeat times on a reset PDA.
+*/
+func runBenchmark(args []string) {
+	if len(args) < 2 {
+		log.Fatal("usage: bench <specification-file> <input-file> [repeat]")
+	}
+	repeat := 1
+	if len(args) == 3 {
+		r, err := strconv.Atoi(args[2])
+		if err != nil || r <= 0 {
+			log.Fatal("repeat should be a positive integer")
+		}
+		repeat = r
+	}
+
+	pdaProcessor := &pda.PDAProcessor{}
+	if _, err := pdaProcessor.Open(args[0]); err != nil {
+		log.Fatal(err)
+	}
+	inputString, _ := readInputFromFile(args[1])
+	inputTokens, err := pdaProcessor.Tokenize(inputString)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var consumed int
+	var elapsed time.Duration
+	accepted := false
+	for i := 0; i < repeat; i++ {
+		pdaProcessor.Reset()
+		start := time.Now()
+		for index, token := range inputTokens {
+			transitionTaken, err := pdaProcessor.Step(index+1, token.Symbol)
+			if err != nil {
+				log.Fatal(err)
+			} else if len(transitionTaken) == 0 {
+				break
+			}
+			consumed++
+		}
+		elapsed += time.Since(start)
+		accepted = pdaProcessor.IsAccepted()
+	}
+
+	fmt.Println("\n************************** Benchmark **************************")
+	fmt.Printf("PDA %q, %d transitions, %d input tokens, %d runs, accepted: %t\n", pdaProcessor.Name, len(pdaProcessor.Transitions), len(inputTokens), repeat, accepted)
+	fmt.Printf("Consumed %d tokens in %v, %.0f tokens/s, %.1f ns/token\n", consumed, elapsed, float64(consumed)/elapsed.Seconds(), float64(elapsed.Nanoseconds())/float64(consumed))
+}
+
+/**
+report whether the PDA specification is deterministic and list the conflicting transitions.
+*/
+func runDeterminismAnalysis(args []string) {
+	if len(args) < 1 {
+		log.Fatal("usage: analyze <specification-file>")
+	}
+
+	pdaProcessor := &pda.PDAProcessor{}
+	if _, err := pdaProcessor.Open(args[0]); err != nil {
+		log.Fatal(err)
+	}
+	report := pdaProcessor.AnalyzeDeterminism()
+
+	fmt.Println("\n************************** Determinism **************************")
+	if report.Deterministic {
+		fmt.Printf("PDA %q is deterministic\n", pdaProcessor.Name)
+		return
+	}
+	fmt.Printf("PDA %q is nondeterministic, %d conflicting transition pairs:\n", pdaProcessor.Name, len(report.Conflicts))
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("transitions[%d] %s and transitions[%d] %s: %s\n", conflict.First, conflict.FirstTransition, conflict.Second, conflict.SecondTransition, conflict.Reason)
+	}
+}
+
+/**
+compile the context-free grammar in grammar file into a PDA specification, written to the specification file or printed.
+*/
+func runGrammarCompiler(args []string) {
+	if len(args) < 1 {
+		log.Fatal("usage: grammar <grammar-file> [specification-file]")
+	}
+
+	grammarJson, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	var grammar pda.Grammar
+	if err := json.Unmarshal(grammarJson, &grammar); err != nil {
+		log.Fatal("couldn't unmarshal grammar: ", err)
+	}
+
+	pdaProcessor, err := grammar.ToPDA(0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	specJson, err := json.MarshalIndent(pdaProcessor, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if len(args) < 2 {
+		fmt.Println(string(specJson))
+		return
+	}
+	if err := ioutil.WriteFile(args[1], specJson, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("PDA %q with %d transitions written to %s\n", pdaProcessor.Name, len(pdaProcessor.Transitions), args[1])
+}
+
+/**
+simplify the PDA specification, the result is written to the optimized file or printed after the removed parts.
+*/
+func runOptimizer(args []string) {
+	if len(args) < 1 {
+		log.Fatal("usage: optimize <specification-file> [optimized-file]")
+	}
+
+	pdaProcessor := &pda.PDAProcessor{}
+	if _, err := pdaProcessor.Open(args[0]); err != nil {
+		log.Fatal(err)
+	}
+	optimized, report, err := pdaProcessor.Optimize()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("\n************************** Optimizer **************************")
+	fmt.Printf("unreachable states: %v\n", report.UnreachableStates)
+	fmt.Printf("dead states: %v\n", report.DeadStates)
+	for _, removed := range report.RemovedTransitions {
+		fmt.Printf("removed transitions[%d] %s: %s\n", removed.Index, removed.Transition, removed.Reason)
+	}
+
+	specJson, err := json.MarshalIndent(optimized, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(args) < 2 {
+		fmt.Println(string(specJson))
+		return
+	}
+	if err := ioutil.WriteFile(args[1], specJson, 0644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("PDA %q with %d states and %d transitions written to %s\n", optimized.Name, len(optimized.States), len(optimized.Transitions), args[1])
+}
+
+/**
+print the state diagram of the PDA, with the path taken on the token stream in input file when it is given.
+*/
+func runGraph(args []string) {
+	if len(args) < 1 {
+		log.Fatal("usage: graph <specification-file> [dot|mermaid] [input-file]")
+	}
+
+	pdaProcessor := &pda.PDAProcessor{}
+	if _, err := pdaProcessor.Open(args[0]); err != nil {
+		log.Fatal(err)
+	}
+	format := pda.PDA_GRAPH_FORMAT_DOT
+	if len(args) > 1 {
+		format = args[1]
+	}
+
+	var trace []pda.TraceStep
+	if len(args) > 2 {
+		inputString, err := readInputFromFile(args[2])
+		if err != nil {
+			log.Fatal(err)
+		}
+		// a rejected input still shows the path up to where it failed
+		trace, _, _ = pdaProcessor.EvaluateInput(inputString)
+	}
+
+	diagram, err := pdaProcessor.RenderGraph(format, trace)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(diagram)
+}
+
+/**
+read input token stream from specified file path
+*/
+func readInputFromFile(inputFilePath string) (string, error) {
+	filebuffer, err := ioutil.ReadFile(inputFilePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return string(filebuffer), nil
+}
+
+func printFinalStatus(pdaProcessor *pda.PDAProcessor, inputString string, acceptedToken bool, transitionsTaken []pda.TraceStep, output []string) {
+	fmt.Println("\n************************** Result **************************")
+	if acceptedToken {
+		fmt.Printf("PDA %q successfully accepted the input: %q \n", pdaProcessor.Name, inputString)
+	} else {
+		fmt.Printf("PDA %q rejected the input: %q \n", pdaProcessor.Name, inputString)
+	}
+
+	fmt.Printf("Final state: %q, Accepting states: %+v, Stack: %+v\n", pdaProcessor.CurrentState, pdaProcessor.AcceptingStates, pdaProcessor.Stack)
+	if len(output) > 0 {
+		fmt.Printf("Output: %q\n", strings.Join(output, pdaProcessor.OutputSeparator))
+	}
+
+	if len(transitionsTaken) <= 0 {
+		fmt.Println("No transition taken")
+	} else {
+		fmt.Println("Transitions taken: ")
+		for _, step := range transitionsTaken {
+			fmt.Println(step)
+		}
+		fmt.Println()
+	}
+
+	//fmt.Print("*************************************************************\n\n")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"github.com/pravin-gayal/pda-processor/pda"
+	"github.com/pravin-gayal/pda-processor/server"
+	"os"
+	"strconv"
+)
+
+var port string
+
+func main() {
+	// verbosity and format of the logs, e.g. PDA_LOG_LEVEL=trace logs every transition evaluated
+	if err := pda.ConfigureLogging(os.Getenv("PDA_LOG_LEVEL"), os.Getenv("PDA_LOG_FORMAT")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// most sessions open at the same time and how long they live, e.g. PDA_MAX_SESSIONS=0 lifts the limit
+	if err := server.ConfigureSessions(os.Getenv("PDA_MAX_SESSIONS"), os.Getenv("PDA_SESSION_IDLE_TTL"), os.Getenv("PDA_SESSION_TTL")); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	server.ConfigureAdminToken(os.Getenv("PDA_ADMIN_TOKEN"))
+
+	if len(port) > 0 {
+		if _, err := strconv.Atoi(port); err != nil {
+			fmt.Print("Invalid Port: Port number should be integers\n\n")
+			os.Exit(1)
+		} else if len(port) < 4 {
+			fmt.Print("Invalid Port: Port number should be at least 4 digit\n\n")
+			os.Exit(1)
+		}
+	}
+
+	server.Run(port)
+}
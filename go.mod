@@ -0,0 +1,10 @@
+module github.com/pravin-gayal/pda-processor
+
+go 1.22
+
+require (
+	github.com/gorilla/handlers v1.5.1
+	github.com/gorilla/mux v1.8.0
+)
+
+require github.com/felixge/httpsnoop v1.0.1 // indirect
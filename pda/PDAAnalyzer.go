@@ -0,0 +1,73 @@
+package pda
+
+import (
+	"fmt"
+)
+
+/**
+TransitionConflict is a pair of transitions that can both apply to the same configuration, identified by their index in the specification.
+*/
+type TransitionConflict struct {
+	First            int        `json:"first"`
+	Second           int        `json:"second"`
+	FirstTransition  Transition `json:"first_transition"`
+	SecondTransition Transition `json:"second_transition"`
+	Reason           string     `json:"reason"`
+}
+
+/**
+DeterminismReport is the result of AnalyzeDeterminism, Deterministic is set when no transitions conflict.
+*/
+type DeterminismReport struct {
+	Deterministic bool                 `json:"deterministic"`
+	Conflicts     []TransitionConflict `json:"conflicts"`
+}
+
+/**
+AnalyzeDeterminism checks whether the specification is deterministic, i.e. no configuration has two applicable transitions.
+Two transitions of the same state conflict when their stack tops overlap (equal, or one of them does not inspect the stack)
+and they read the same input or one of them is an epsilon move. A deterministic PDA silently takes the first of them.
+*/
+func (pdaProcessor *PDAProcessor) AnalyzeDeterminism() DeterminismReport {
+	report := DeterminismReport{Deterministic: true, Conflicts: []TransitionConflict{}}
+	for i, first := range pdaProcessor.Transitions {
+		for j := i + 1; j < len(pdaProcessor.Transitions); j++ {
+			second := pdaProcessor.Transitions[j]
+			if first.State != second.State {
+				continue
+			}
+			if first.Pop != "" && second.Pop != "" && first.Pop != second.Pop {
+				continue
+			}
+
+			var reading string
+			switch {
+			case first.Input == second.Input && first.Input == "":
+				reading = "both are epsilon moves"
+			case first.Input == second.Input:
+				reading = fmt.Sprintf("both read %q", first.Input)
+			case first.Input == "" || second.Input == "":
+				reading = "an epsilon move overlaps with a move reading input"
+			default:
+				continue
+			}
+
+			top := "any stack top"
+			if first.Pop != "" {
+				top = fmt.Sprintf("stack top %q", first.Pop)
+			} else if second.Pop != "" {
+				top = fmt.Sprintf("stack top %q", second.Pop)
+			}
+
+			report.Deterministic = false
+			report.Conflicts = append(report.Conflicts, TransitionConflict{
+				First:            i,
+				Second:           j,
+				FirstTransition:  first,
+				SecondTransition: second,
+				Reason:           fmt.Sprintf("in state %q with %s %s", first.State, top, reading),
+			})
+		}
+	}
+	return report
+}
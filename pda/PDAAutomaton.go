@@ -0,0 +1,48 @@
+/**
+Package pda is the pushdown automaton engine of the PDA processor: specification types, validation, the evaluation of
+token streams and the analyses built on top of it (determinism, grammars, emptiness, optimization, state diagrams).
+
+A PDA is loaded from its JSON specification and then fed tokens one at a time,
+
+	pdaProcessor := &pda.PDAProcessor{}
+	if err := pdaProcessor.Load(specJson); err != nil {
+		...
+	}
+	pdaProcessor.Put(0, "0")
+	pdaProcessor.Put(1, "1")
+	pdaProcessor.EOS(1)
+	accepted := pdaProcessor.IsAccepted()
+
+or given a whole token stream at once with EvaluateInput. A PDAProcessor is not safe for concurrent use.
+*/
+package pda
+
+/**
+Automaton is the interface of a running PDA as it is used by clients presenting tokens, it is kept stable across
+releases. Positions are 0-based, tokens presented ahead of the next expected position wait until the gap is filled.
+*/
+type Automaton interface {
+	// Reset puts the automaton back at its start state with an empty stack.
+	Reset() error
+	// Put presents token at position and returns the current state after consuming it, "" when it was queued.
+	Put(position int, token string) (string, error)
+	// EOS announces that position is the position of the last token.
+	EOS(position int) error
+	// IsAccepted tells whether the automaton accepts the tokens consumed so far.
+	IsAccepted() bool
+	// Peek returns up to k symbols from the top of the stack, bottom to top, so the top of the stack comes last.
+	Peek(k int) []string
+	// State returns the current state.
+	State() string
+	// QueuedTokens returns the tokens waiting for earlier positions, in position order.
+	QueuedTokens() []string
+	// TransitionsTaken returns the moves that led to the current configuration, oldest first.
+	TransitionsTaken() []TraceStep
+	// Output returns the output written by the moves that led to the current configuration, joined with the
+	// output_separator of the specification.
+	Output() string
+	// Close releases the resources held by the automaton.
+	Close()
+}
+
+var _ Automaton = (*PDAProcessor)(nil)
@@ -0,0 +1,105 @@
+package pda
+
+import (
+	"errors"
+	"fmt"
+)
+
+/**
+state of the PDA right before the token at position was consumed, including the steps counted against
+max_total_steps so far. The clock keeps running across a rollback and an EOS presented later is kept, so neither is
+part of the checkpoint.
+*/
+type checkpoint struct {
+	position             int
+	configurations       []Configuration
+	pendingTokenQueue    map[int]string
+	lastConsumedPosition int
+	steps                int
+}
+
+/**
+record a checkpoint before the token at position is consumed, only the most recent max_checkpoints are kept.
+Configurations are immutable so the checkpoint shares them with the live PDA.
+*/
+func (pdaProcessor *PDAProcessor) saveCheckpoint(position int) {
+	queue := make(map[int]string, len(pdaProcessor.PendingTokenQueue))
+	for queuedPosition, token := range pdaProcessor.PendingTokenQueue {
+		queue[queuedPosition] = token
+	}
+	pdaProcessor.checkpoints = append(pdaProcessor.checkpoints, checkpoint{
+		position:             position,
+		configurations:       pdaProcessor.Configurations,
+		pendingTokenQueue:    queue,
+		lastConsumedPosition: pdaProcessor.LastConsumedPosition,
+		steps:                pdaProcessor.Steps,
+	})
+	if len(pdaProcessor.checkpoints) > pdaProcessor.maxCheckpoints() {
+		pdaProcessor.checkpoints = pdaProcessor.checkpoints[1:]
+	}
+}
+
+/**
+CheckpointPositions returns the positions of the tokens which can still be retracted, oldest first.
+*/
+func (pdaProcessor *PDAProcessor) CheckpointPositions() []int {
+	positions := make([]int, len(pdaProcessor.checkpoints))
+	for i, checkpoint := range pdaProcessor.checkpoints {
+		positions[i] = checkpoint.position
+	}
+	return positions
+}
+
+/**
+Rollback retracts the last steps consumed tokens.
+*/
+func (pdaProcessor *PDAProcessor) Rollback(steps int) error {
+	if steps <= 0 {
+		return errors.New("number of steps to roll back should be a positive integer")
+	}
+	if steps > len(pdaProcessor.checkpoints) {
+		return fmt.Errorf("can not roll back %d steps, only %d consumed tokens can be retracted", steps, len(pdaProcessor.checkpoints))
+	}
+	pdaProcessor.restoreCheckpoint(len(pdaProcessor.checkpoints) - steps)
+	return nil
+}
+
+/**
+RollbackToPosition retracts the token consumed at position and every token consumed after it.
+*/
+func (pdaProcessor *PDAProcessor) RollbackToPosition(position int) error {
+	for i, checkpoint := range pdaProcessor.checkpoints {
+		if checkpoint.position == position {
+			pdaProcessor.restoreCheckpoint(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("no checkpoint for position %d, checkpoints are kept for the last %d consumed tokens", position, pdaProcessor.maxCheckpoints())
+}
+
+/**
+go back to the state saved in checkpoints[i] and drop it together with every later checkpoint. Tokens which were
+waiting in the pending queue at that time are queued again, next to the ones presented since. The steps of the
+retracted tokens no longer count against the total step budget.
+*/
+func (pdaProcessor *PDAProcessor) restoreCheckpoint(i int) {
+	checkpoint := pdaProcessor.checkpoints[i]
+	pdaProcessor.checkpoints = pdaProcessor.checkpoints[:i]
+
+	pdaProcessor.Configurations = checkpoint.configurations
+	pdaProcessor.syncCurrentConfiguration()
+	pdaProcessor.LastConsumedPosition = checkpoint.lastConsumedPosition
+	pdaProcessor.Steps = checkpoint.steps
+	for position, token := range checkpoint.pendingTokenQueue {
+		pdaProcessor.PendingTokenQueue[position] = token
+	}
+	pdaProcessor.PDAFailedInLastEvaluation = false
+	pdaProcessor.Logger().Info("rolled back", "position", checkpoint.position, "state", pdaProcessor.CurrentState)
+}
+
+func (pdaProcessor *PDAProcessor) maxCheckpoints() int {
+	if pdaProcessor.MaxCheckpoints > 0 {
+		return pdaProcessor.MaxCheckpoints
+	}
+	return PDA_CHECKPOINT_LIMIT
+}
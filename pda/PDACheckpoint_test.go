@@ -0,0 +1,91 @@
+package pda
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// a^n b^n, every a pushes and every b pops
+const countingTransitions = `
+	["q0", "a", null, "q0", "a"],
+	["q0", "b", "a", "q1", null],
+	["q1", "b", "a", "q1", null]`
+
+func TestRollback(t *testing.T) {
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "f"`, countingTransitions, PDA_MODE_DETERMINISTIC))
+	for position, token := range []string{"a", "a", "b"} {
+		if _, err := pdaProcessor.Put(position, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := pdaProcessor.Put(4, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	// retracting b puts a a back on the stack, the queued b stays queued
+	if err := pdaProcessor.Rollback(1); err != nil {
+		t.Fatal(err)
+	}
+	if pdaProcessor.State() != "q0" || !reflect.DeepEqual(pdaProcessor.Peek(3), []string{"a", "a"}) || pdaProcessor.LastConsumedPosition != 2 {
+		t.Fatalf("got state %s and stack %v at position %d after rolling back b", pdaProcessor.State(), pdaProcessor.Peek(3), pdaProcessor.LastConsumedPosition)
+	}
+	if !reflect.DeepEqual(pdaProcessor.QueuedTokens(), []string{"b"}) || !reflect.DeepEqual(pdaProcessor.CheckpointPositions(), []int{0, 1}) {
+		t.Fatalf("got queued tokens %v and checkpoints %v", pdaProcessor.QueuedTokens(), pdaProcessor.CheckpointPositions())
+	}
+
+	// a wrong token fails the evaluation until it is rolled back
+	if _, err := pdaProcessor.Put(2, "c"); err == nil || !pdaProcessor.PDAFailedInLastEvaluation {
+		t.Fatalf("got %v, want the evaluation to fail", err)
+	}
+	if err := pdaProcessor.RollbackToPosition(1); err != nil {
+		t.Fatal(err)
+	}
+	if pdaProcessor.PDAFailedInLastEvaluation || !reflect.DeepEqual(pdaProcessor.Peek(3), []string{"a"}) {
+		t.Fatalf("got failed %t and stack %v after rolling back to position 1", pdaProcessor.PDAFailedInLastEvaluation, pdaProcessor.Peek(3))
+	}
+
+	for _, err := range []error{pdaProcessor.Rollback(0), pdaProcessor.Rollback(2), pdaProcessor.RollbackToPosition(5)} {
+		if err == nil {
+			t.Error("rollback beyond the checkpoints succeeded")
+		}
+	}
+}
+
+func TestRollbackReturnsSteps(t *testing.T) {
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "f"`, countingTransitions, PDA_MODE_DETERMINISTIC))
+	pdaProcessor.MaxTotalSteps = 3
+
+	// a token retracted over and over again never uses up the budget
+	for i := 0; i < 10; i++ {
+		if _, err := pdaProcessor.Put(0, "a"); err != nil {
+			t.Fatalf("round %d: %v", i, err)
+		}
+		if err := pdaProcessor.Rollback(1); err != nil {
+			t.Fatal(err)
+		}
+		if pdaProcessor.Steps != 0 {
+			t.Fatalf("round %d: %d steps left after the rollback, want 0", i, pdaProcessor.Steps)
+		}
+	}
+
+	for position := 0; position < 3; position++ {
+		if _, err := pdaProcessor.Put(position, "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := pdaProcessor.Put(3, "a")
+	var limitError *LimitError
+	if !errors.As(err, &limitError) || limitError.Limit != "max_total_steps" {
+		t.Fatalf("got %v, want the total step budget to run out", err)
+	}
+	if err := pdaProcessor.RollbackToPosition(1); err != nil {
+		t.Fatal(err)
+	}
+	if pdaProcessor.Steps != 1 {
+		t.Fatalf("got %d steps after rolling back to position 1, want 1", pdaProcessor.Steps)
+	}
+	if _, err := pdaProcessor.Put(1, "b"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,48 @@
+package pda
+
+// limits of a PDA and of the analyses run on it
+const PDA_PENDING_QUEUE_LENGTH int = 100        // default for max_pending_tokens
+const PDA_CHECKPOINT_LIMIT int = 100            // default for max_checkpoints
+const PDA_MAX_STACK_DEPTH int = 100000          // default for max_stack_depth, a few megabytes of stack per session
+const PDA_MAX_STEPS_PER_TOKEN int = 100000      // default for max_steps_per_token, max_total_steps is unlimited by default
+const PDA_GRAMMAR_MAX_PRODUCTIONS int = 1000000 // bound on the productions of a PDA converted to a grammar, before pruning
+const PDA_EXAMPLES_MAX_PREFIXES int = 100000    // bound on the inputs the example enumerator extends
+const PDA_EXAMPLES_MAX_LENGTH int = 10          // default for max_len of the examples
+const PDA_EXAMPLES_LIMIT int = 20               // default for limit of the examples
+const PDA_WITNESS_MAX_LENGTH int = 10000        // longest shortest accepted input the emptiness check spells out
+const PDA_WITNESS_SEARCH_LENGTH int = 20        // input length searched when the grammar witness is rejected
+
+// values of the mode of a specification
+const PDA_MODE_DETERMINISTIC string = "deterministic"
+const PDA_MODE_NONDETERMINISTIC string = "nondeterministic"
+
+// values of the acceptance of a specification
+const PDA_ACCEPTANCE_FINAL_STATE string = "final_state"
+const PDA_ACCEPTANCE_EMPTY_STACK string = "empty_stack"
+const PDA_ACCEPTANCE_BOTH string = "both"
+
+// values of the type of a tokenizer
+const PDA_TOKENIZER_WHITESPACE string = "whitespace"
+const PDA_TOKENIZER_CHARACTER string = "character"
+const PDA_TOKENIZER_LEXER string = "lexer"
+
+// codes of the diagnostics of specifications and grammars
+const PDA_DIAGNOSTIC_REQUIRED string = "required"
+const PDA_DIAGNOSTIC_DUPLICATE string = "duplicate"
+const PDA_DIAGNOSTIC_INVALID_VALUE string = "invalid_value"
+const PDA_DIAGNOSTIC_UNDECLARED_STATE string = "undeclared_state"
+const PDA_DIAGNOSTIC_UNDECLARED_INPUT_SYMBOL string = "undeclared_input_symbol"
+const PDA_DIAGNOSTIC_UNDECLARED_STACK_SYMBOL string = "undeclared_stack_symbol"
+const PDA_DIAGNOSTIC_MALFORMED_TRANSITION string = "malformed_transition"
+const PDA_DIAGNOSTIC_UNDECLARED_GRAMMAR_SYMBOL string = "undeclared_grammar_symbol"
+const PDA_DIAGNOSTIC_INVALID_JSON string = "invalid_json"
+
+const PDA_SNAPSHOT_VERSION int = 1 // bumped whenever the session snapshot format changes
+
+// formats of the logs
+const PDA_LOG_FORMAT_TEXT string = "text"
+const PDA_LOG_FORMAT_JSON string = "json"
+
+// formats of the state diagram
+const PDA_GRAPH_FORMAT_DOT string = "dot"
+const PDA_GRAPH_FORMAT_MERMAID string = "mermaid"
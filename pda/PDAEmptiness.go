@@ -0,0 +1,175 @@
+package pda
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+/**
+EmptinessReport tells whether the PDA accepts any input and, if so, a shortest accepted input as a list of tokens. Witness is null for an
+empty language and when no witness could be found, Note then explains why.
+*/
+type EmptinessReport struct {
+	Empty   bool     `json:"empty"`
+	Witness []string `json:"witness"`
+	Note    string   `json:"note,omitempty"`
+}
+
+/**
+CheckEmptiness decides whether the language of the PDA is empty through the productive nonterminals of its grammar and derives a
+shortest accepted input from it. The grammar follows every applicable transition, so the witness is checked by running
+the PDA; a deterministic PDA with conflicting transitions which rejects it is searched breadth first instead.
+*/
+func (pdaProcessor *PDAProcessor) CheckEmptiness() EmptinessReport {
+	return pdaProcessor.CheckEmptinessWithin(PDA_GRAMMAR_MAX_PRODUCTIONS)
+}
+
+/**
+CheckEmptinessWithin decides whether the language of the PDA is empty like CheckEmptiness, building a grammar of at
+most maxProductions productions before pruning. A PDA none of whose accepting states can be reached from the start
+state, even regardless of the stack, is reported empty without building the grammar at all.
+*/
+func (pdaProcessor *PDAProcessor) CheckEmptinessWithin(maxProductions int) EmptinessReport {
+	if !pdaProcessor.acceptingStateReachable() {
+		return EmptinessReport{Empty: true}
+	}
+
+	grammar, err := pdaProcessor.toGrammar(maxProductions)
+	if err == nil {
+		if len(grammar.Productions) == 0 {
+			// a deterministic PDA only takes some of the moves of the grammar, so it accepts nothing either
+			return EmptinessReport{Empty: true}
+		}
+		witness, length := shortestDerivation(grammar)
+		if witness == nil {
+			return EmptinessReport{Note: fmt.Sprintf("the shortest accepted input has %d tokens, more than the %d a witness is built for", length, PDA_WITNESS_MAX_LENGTH)}
+		}
+		if pdaProcessor.acceptsTokens(witness) {
+			return EmptinessReport{Witness: witness}
+		}
+	}
+
+	report, searchErr := pdaProcessor.Examples(PDA_WITNESS_SEARCH_LENGTH, 1)
+	if len(report.Examples) > 0 {
+		return EmptinessReport{Witness: report.Examples[0]}
+	}
+	if searchErr != nil {
+		return EmptinessReport{Note: "the PDA can not be run to look for an accepted input: " + searchErr.Error()}
+	}
+	if err != nil {
+		return EmptinessReport{Note: fmt.Sprintf("no accepted input of at most %d tokens was found and the grammar of the PDA is too large to decide the rest: %v", PDA_WITNESS_SEARCH_LENGTH, err)}
+	}
+	return EmptinessReport{Note: fmt.Sprintf("no accepted input of at most %d tokens was found, the PDA only accepts inputs along transitions it skips in deterministic mode", PDA_WITNESS_SEARCH_LENGTH)}
+}
+
+/**
+tell whether an accepting state can be reached from the start state through the transitions, regardless of the stack.
+Every state can accept by empty stack.
+*/
+func (pdaProcessor *PDAProcessor) acceptingStateReachable() bool {
+	return pdaProcessor.liveStates()[pdaProcessor.StartState]
+}
+
+/**
+run a copy of the PDA on tokens and tell whether it accepts them.
+*/
+func (pdaProcessor *PDAProcessor) acceptsTokens(tokens []string) bool {
+	scratch := *pdaProcessor
+	if err := scratch.reset(false); err != nil {
+		return false
+	}
+	for index, token := range tokens {
+		transitionTaken, err := scratch.Step(index+1, token)
+		if err != nil || transitionTaken == "" {
+			return false
+		}
+	}
+	return scratch.acceptsConfigurations(scratch.Configurations)
+}
+
+/**
+return a shortest terminal string derived from the start symbol of a grammar whose nonterminals are all productive,
+with Knuth's generalization of Dijkstra's algorithm: a nonterminal is settled at the smallest length over productions
+whose body nonterminals are settled already, so expanding the chosen productions always terminates.
+The string is nil when it is longer than PDA_WITNESS_MAX_LENGTH, its length is returned in any case.
+*/
+func shortestDerivation(grammar Grammar) ([]string, int) {
+	pending := make([]int, len(grammar.Productions))
+	lengths := make([]int, len(grammar.Productions))
+	usedBy := make(map[string][]int)
+	isTerminal := make(map[string]bool)
+	for _, terminal := range grammar.Terminals {
+		isTerminal[terminal] = true
+	}
+
+	candidates := &derivationQueue{}
+	for i, production := range grammar.Productions {
+		for _, symbol := range production.Body {
+			if isTerminal[symbol] {
+				lengths[i]++
+			} else {
+				pending[i]++
+				usedBy[symbol] = append(usedBy[symbol], i)
+			}
+		}
+		if pending[i] == 0 {
+			heap.Push(candidates, derivation{production.Head, i, lengths[i]})
+		}
+	}
+
+	shortest := make(map[string]derivation)
+	for candidates.Len() > 0 {
+		candidate := heap.Pop(candidates).(derivation)
+		if _, settled := shortest[candidate.nonterminal]; settled {
+			continue
+		}
+		shortest[candidate.nonterminal] = candidate
+		for _, i := range usedBy[candidate.nonterminal] {
+			pending[i]--
+			lengths[i] += candidate.length
+			if pending[i] == 0 {
+				heap.Push(candidates, derivation{grammar.Productions[i].Head, i, lengths[i]})
+			}
+		}
+	}
+
+	start, found := shortest[grammar.Start]
+	if !found || start.length > PDA_WITNESS_MAX_LENGTH {
+		return nil, start.length
+	}
+	witness := []string{}
+	var expand func(symbol string)
+	expand = func(symbol string) {
+		if isTerminal[symbol] {
+			witness = append(witness, symbol)
+			return
+		}
+		for _, next := range grammar.Productions[shortest[symbol].production].Body {
+			expand(next)
+		}
+	}
+	expand(grammar.Start)
+	return witness, start.length
+}
+
+/**
+shortest known derivation of a nonterminal, through the production at the given index.
+*/
+type derivation struct {
+	nonterminal string
+	production  int
+	length      int
+}
+
+type derivationQueue []derivation
+
+func (queue derivationQueue) Len() int            { return len(queue) }
+func (queue derivationQueue) Less(i, j int) bool  { return queue[i].length < queue[j].length }
+func (queue derivationQueue) Swap(i, j int)       { queue[i], queue[j] = queue[j], queue[i] }
+func (queue *derivationQueue) Push(x interface{}) { *queue = append(*queue, x.(derivation)) }
+func (queue *derivationQueue) Pop() interface{} {
+	old := *queue
+	last := old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return last
+}
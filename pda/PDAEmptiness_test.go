@@ -0,0 +1,76 @@
+package pda
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckEmptinessOfEmptyLanguages(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		transitions string
+	}{
+		// f can not be reached at all, reported without building the grammar
+		{"unreachable accepting state", `{"state": "q0", "input": "a", "next_state": "q1"}`},
+		// f is reached with X on the stack, which is never popped again
+		{"stack never emptied", `["q0", "a", null, "f", "X"], ["f", "b", "a", "f", null]`},
+	} {
+		pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "f"`, test.transitions, PDA_MODE_NONDETERMINISTIC))
+		report := pdaProcessor.CheckEmptiness()
+		if !report.Empty || report.Witness != nil {
+			t.Errorf("%s: got %+v, want an empty language", test.name, report)
+		}
+	}
+}
+
+func TestCheckEmptinessWitness(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		specification []byte
+		length        int
+	}{
+		{"even palindromes", specificationWith(`"q0", "q1", "q2", "f"`, evenPalindromeTransitions, PDA_MODE_NONDETERMINISTIC), 0},
+		{"a^n b^n with n >= 1", specificationWith(`"q0", "q1", "f"`, `
+			["q0", "a", null, "q1", "a"],
+			["q1", "a", null, "q1", "a"],
+			["q1", "b", "a", "f", null],
+			["f", "b", "a", "f", null]`, PDA_MODE_DETERMINISTIC), 2},
+		{"a^3 b", specificationWith(`"q0", "q1", "q2", "q3", "f"`, `
+			{"state": "q0", "input": "a", "next_state": "q1"},
+			{"state": "q1", "input": "a", "next_state": "q2"},
+			{"state": "q2", "input": "a", "next_state": "q3"},
+			{"state": "q3", "input": "b", "next_state": "f"},
+			{"state": "q0", "input": "b", "next_state": "q0"}`, PDA_MODE_DETERMINISTIC), 4},
+	} {
+		pdaProcessor := loadSpecification(t, test.specification)
+		report := pdaProcessor.CheckEmptiness()
+		if report.Empty || report.Witness == nil || len(report.Witness) != test.length {
+			t.Fatalf("%s: got %+v, want a witness of %d tokens", test.name, report, test.length)
+		}
+		if !pdaProcessor.acceptsTokens(report.Witness) {
+			t.Fatalf("%s: witness %q is rejected", test.name, report.Witness)
+		}
+	}
+}
+
+func TestCheckEmptinessFallsBackToSearch(t *testing.T) {
+	// the grammar derives "a" through the second transition, which the deterministic PDA never takes
+	skipped := `
+		{"state": "q0", "input": "a", "next_state": "q1"},
+		{"state": "q0", "input": "a", "next_state": "f"}`
+
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f"`, skipped+`,
+		{"state": "q0", "input": "b", "next_state": "q2"},
+		{"state": "q2", "input": "a", "next_state": "f"}`, PDA_MODE_DETERMINISTIC))
+	report := pdaProcessor.CheckEmptiness()
+	if report.Empty || strings.Join(report.Witness, " ") != "b a" {
+		t.Fatalf("got %+v, want the witness b a found by the search", report)
+	}
+
+	// without another way to f the search finds nothing, the language is not reported empty though
+	pdaProcessor = loadSpecification(t, specificationWith(`"q0", "q1", "f"`, skipped, PDA_MODE_DETERMINISTIC))
+	report = pdaProcessor.CheckEmptiness()
+	if report.Empty || report.Witness != nil || !strings.Contains(report.Note, "skips in deterministic mode") {
+		t.Fatalf("got %+v, want a note on the skipped transitions", report)
+	}
+}
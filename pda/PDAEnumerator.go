@@ -0,0 +1,80 @@
+package pda
+
+/**
+ExamplesReport holds the accepted inputs found by the enumerator, each as the list of its input symbols so that it does not
+depend on how a tokenizer would split it. Complete is set when the search was not cut short by the limit or the search
+budget, i.e. every accepted input up to the maximum length is listed.
+*/
+type ExamplesReport struct {
+	Examples [][]string `json:"examples"`
+	Complete bool       `json:"complete"`
+}
+
+/**
+input read so far together with the configurations the PDA can be in after reading it.
+*/
+type examplePrefix struct {
+	tokens         []string
+	configurations []Configuration
+}
+
+/**
+Examples lists up to limit accepted inputs of at most maxLength tokens, shortest first and in the order of the input alphabet.
+The search runs the PDA breadth first on every input whose prefix still leaves a live configuration, so it follows the
+mode of the PDA exactly. Moves running into a limit of the PDA end that branch and leave the report incomplete.
+*/
+func (pdaProcessor *PDAProcessor) Examples(maxLength int, limit int) (ExamplesReport, error) {
+	report := ExamplesReport{Examples: [][]string{}, Complete: true}
+
+	// run the search on a copy so that the runtime state of the PDA is left as it is
+	scratch := *pdaProcessor
+	if err := scratch.reset(false); err != nil {
+		return report, err
+	}
+
+	queue := []examplePrefix{{configurations: scratch.Configurations}}
+	expanded := 0
+	for len(queue) > 0 {
+		prefix := queue[0]
+		queue = queue[1:]
+
+		if scratch.acceptsConfigurations(prefix.configurations) {
+			if len(report.Examples) == limit {
+				report.Complete = false
+				break
+			}
+			report.Examples = append(report.Examples, append([]string{}, prefix.tokens...))
+		}
+		if len(prefix.tokens) == maxLength {
+			continue
+		}
+		if expanded == PDA_EXAMPLES_MAX_PREFIXES {
+			report.Complete = false
+			break
+		}
+		expanded++
+
+		for _, symbol := range scratch.InputAlphabet {
+			scratch.Configurations = prefix.configurations
+			transitionTaken, err := scratch.Step(len(prefix.tokens)+1, symbol)
+			if err != nil {
+				report.Complete = false
+				continue
+			}
+			if transitionTaken != "" {
+				tokens := append(append(make([]string, 0, len(prefix.tokens)+1), prefix.tokens...), symbol)
+				queue = append(queue, examplePrefix{tokens: tokens, configurations: scratch.Configurations})
+			}
+		}
+	}
+	return report, nil
+}
+
+func (pdaProcessor *PDAProcessor) acceptsConfigurations(configurations []Configuration) bool {
+	for _, configuration := range configurations {
+		if isAcceptingConfiguration(pdaProcessor, configuration) {
+			return true
+		}
+	}
+	return false
+}
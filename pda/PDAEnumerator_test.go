@@ -0,0 +1,27 @@
+package pda
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExamples(t *testing.T) {
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f"`, evenPalindromeTransitions, PDA_MODE_NONDETERMINISTIC))
+
+	report, err := pdaProcessor.Examples(4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{}, {"a", "a"}, {"b", "b"}, {"a", "a", "a", "a"}, {"a", "b", "b", "a"}, {"b", "a", "a", "b"}, {"b", "b", "b", "b"}}
+	if !reflect.DeepEqual(report.Examples, want) || !report.Complete {
+		t.Fatalf("got %v complete %t, want %v complete", report.Examples, report.Complete, want)
+	}
+
+	report, err = pdaProcessor.Examples(4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Examples, want[:3]) || report.Complete {
+		t.Fatalf("got %v complete %t, want the first 3 incomplete", report.Examples, report.Complete)
+	}
+}
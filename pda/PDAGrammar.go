@@ -0,0 +1,389 @@
+package pda
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+Grammar is a context-free grammar, the nonterminals are the heads of its productions.
+The tokenizer, when given, is copied to the PDA compiled from the grammar.
+*/
+type Grammar struct {
+	Name        string       `json:"name"`
+	Terminals   []string     `json:"terminals"`
+	Start       string       `json:"start"`
+	Productions []Production `json:"productions"`
+	Tokenizer   *Tokenizer   `json:"tokenizer,omitempty"`
+}
+
+/**
+Production is the production Head -> Body, an empty Body is an epsilon production.
+*/
+type Production struct {
+	Head string   `json:"head"`
+	Body []string `json:"body"`
+}
+
+/**
+return the nonterminals of the grammar in the order they first appear as a head.
+*/
+func (grammar *Grammar) nonterminals() []string {
+	var nonterminals []string
+	for _, production := range grammar.Productions {
+		if production.Head != "" && !findInArray(nonterminals, production.Head) {
+			nonterminals = append(nonterminals, production.Head)
+		}
+	}
+	return nonterminals
+}
+
+/**
+Validate statically checks the grammar and returns the list of problems found, empty when the grammar is valid.
+*/
+func (grammar *Grammar) Validate() []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(path string, code string, message string) {
+		diagnostics = append(diagnostics, Diagnostic{Path: path, Code: code, Message: message})
+	}
+
+	if grammar.Name == "" {
+		report("name", PDA_DIAGNOSTIC_REQUIRED, "grammar name field cannot be empty")
+	}
+	if len(grammar.Terminals) == 0 {
+		report("terminals", PDA_DIAGNOSTIC_REQUIRED, "grammar terminals field cannot be empty")
+	}
+	if len(grammar.Productions) == 0 {
+		report("productions", PDA_DIAGNOSTIC_REQUIRED, "grammar productions field cannot be empty")
+	}
+
+	nonterminals := grammar.nonterminals()
+	seen := make(map[string]bool)
+	for i, terminal := range grammar.Terminals {
+		path := fmt.Sprintf("terminals[%d]", i)
+		if terminal == "" {
+			report(path, PDA_DIAGNOSTIC_REQUIRED, "empty symbol in terminals")
+		} else if seen[terminal] {
+			report(path, PDA_DIAGNOSTIC_DUPLICATE, fmt.Sprintf("%q is declared more than once in terminals", terminal))
+		} else if findInArray(nonterminals, terminal) {
+			report(path, PDA_DIAGNOSTIC_DUPLICATE, fmt.Sprintf("%q is both a terminal and the head of a production", terminal))
+		}
+		seen[terminal] = true
+	}
+
+	if grammar.Start == "" {
+		report("start", PDA_DIAGNOSTIC_REQUIRED, "grammar start symbol cannot be empty")
+	} else if !findInArray(nonterminals, grammar.Start) {
+		report("start", PDA_DIAGNOSTIC_UNDECLARED_GRAMMAR_SYMBOL, fmt.Sprintf("start symbol %q is not the head of any production", grammar.Start))
+	}
+
+	for i, production := range grammar.Productions {
+		path := fmt.Sprintf("productions[%d]", i)
+		if production.Head == "" {
+			report(path+".head", PDA_DIAGNOSTIC_REQUIRED, "production head cannot be empty")
+		}
+		for j, symbol := range production.Body {
+			if !findInArray(grammar.Terminals, symbol) && !findInArray(nonterminals, symbol) {
+				report(fmt.Sprintf("%s.body[%d]", path, j), PDA_DIAGNOSTIC_UNDECLARED_GRAMMAR_SYMBOL, fmt.Sprintf("symbol %q is neither a terminal nor the head of a production", symbol))
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+/**
+return the nonterminals deriving the empty input.
+*/
+func (grammar *Grammar) nullable() map[string]bool {
+	nullable := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, production := range grammar.Productions {
+			if nullable[production.Head] {
+				continue
+			}
+			allNullable := true
+			for _, symbol := range production.Body {
+				allNullable = allNullable && nullable[symbol]
+			}
+			if allNullable {
+				nullable[production.Head] = true
+				changed = true
+			}
+		}
+	}
+	return nullable
+}
+
+/**
+tell whether the grammar is left recursive, i.e. has a nonterminal B in A -> x B y with nullable x from which A can be
+derived again as the leftmost symbol.
+*/
+func (grammar *Grammar) leftRecursive() bool {
+	nullable := grammar.nullable()
+
+	// leftmost[A] lists the nonterminals that can start a body of A
+	leftmost := make(map[string][]string)
+	for _, production := range grammar.Productions {
+		for _, symbol := range production.Body {
+			if findInArray(grammar.Terminals, symbol) {
+				break
+			}
+			leftmost[production.Head] = append(leftmost[production.Head], symbol)
+			if !nullable[symbol] {
+				break
+			}
+		}
+	}
+	reaches := func(from string, to string) bool {
+		visited := map[string]bool{from: true}
+		pending := []string{from}
+		for len(pending) > 0 {
+			symbol := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if symbol == to {
+				return true
+			}
+			for _, next := range leftmost[symbol] {
+				if !visited[next] {
+					visited[next] = true
+					pending = append(pending, next)
+				}
+			}
+		}
+		return false
+	}
+
+	for _, production := range grammar.Productions {
+		for _, symbol := range production.Body {
+			if findInArray(grammar.Terminals, symbol) {
+				break
+			}
+			if reaches(symbol, production.Head) {
+				return true
+			}
+			if !nullable[symbol] {
+				break
+			}
+		}
+	}
+	return false
+}
+
+/**
+return an equivalent grammar without left recursion. Epsilon and unit productions are removed first, then with the
+nonterminals in order every production A -> B x of a nonterminal B ordered before A is replaced by A -> y x for every
+production B -> y, which leaves only immediate left recursion A -> A x | y. It is replaced by A -> y A' and
+A' -> x A' | ε with a fresh nonterminal A'. A grammar accepting the empty input starts from a fresh start symbol which
+derives it. Fails when the grammar grows beyond PDA_GRAMMAR_MAX_PRODUCTIONS productions.
+*/
+func (grammar *Grammar) withoutLeftRecursion() (Grammar, error) {
+	nonterminals := grammar.nonterminals()
+	taken := append(append([]string{}, grammar.Terminals...), nonterminals...)
+	isNonterminal := make(map[string]bool)
+	for _, nonterminal := range nonterminals {
+		isNonterminal[nonterminal] = true
+	}
+	nullable := grammar.nullable()
+	bodies := newProductionSet()
+
+	// without epsilon productions, every body also appears without each of its nullable symbols
+	for _, production := range grammar.Productions {
+		variants := [][]string{{}}
+		for _, symbol := range production.Body {
+			count := len(variants)
+			for i := 0; i < count; i++ {
+				if nullable[symbol] {
+					variants = append(variants, variants[i])
+				}
+				variants[i] = append(append([]string{}, variants[i]...), symbol)
+			}
+		}
+		for _, body := range variants {
+			if len(body) > 0 && !(len(body) == 1 && body[0] == production.Head) {
+				bodies.add(production.Head, body)
+			}
+		}
+	}
+
+	// without unit productions, A gets the other productions of every nonterminal it derives through unit productions
+	withoutUnits := newProductionSet()
+	for _, head := range nonterminals {
+		reached := []string{head}
+		for i := 0; i < len(reached); i++ {
+			for _, body := range bodies.of(reached[i]) {
+				if len(body) == 1 && isNonterminal[body[0]] {
+					if !findInArray(reached, body[0]) {
+						reached = append(reached, body[0])
+					}
+				} else {
+					withoutUnits.add(head, body)
+				}
+			}
+		}
+	}
+	bodies = withoutUnits
+
+	var fresh []string
+	for i, head := range nonterminals {
+		for _, earlier := range nonterminals[:i] {
+			substituted := newProductionSet()
+			for _, body := range bodies.of(head) {
+				if body[0] != earlier {
+					substituted.add(head, body)
+					continue
+				}
+				for _, prefix := range bodies.of(earlier) {
+					substituted.add(head, append(append([]string{}, prefix...), body[1:]...))
+				}
+			}
+			bodies.replace(head, substituted.of(head))
+			if bodies.size > PDA_GRAMMAR_MAX_PRODUCTIONS {
+				return Grammar{}, fmt.Errorf("grammar would have more than %d productions without left recursion", PDA_GRAMMAR_MAX_PRODUCTIONS)
+			}
+		}
+
+		var recursive, others [][]string
+		for _, body := range bodies.of(head) {
+			if body[0] == head {
+				recursive = append(recursive, body[1:])
+			} else {
+				others = append(others, body)
+			}
+		}
+		if len(recursive) == 0 {
+			continue
+		}
+		tail := freshName(head+"'", append(taken, fresh...))
+		fresh = append(fresh, tail)
+		replaced := newProductionSet()
+		for _, body := range others {
+			replaced.add(head, append(append([]string{}, body...), tail))
+		}
+		bodies.replace(head, replaced.of(head))
+		for _, body := range recursive {
+			bodies.add(tail, append(append([]string{}, body...), tail))
+		}
+		bodies.add(tail, []string{})
+	}
+
+	start := grammar.Start
+	if nullable[grammar.Start] {
+		start = freshName(grammar.Start+"'", append(taken, fresh...))
+		fresh = append(fresh, start)
+		bodies.add(start, []string{grammar.Start})
+		bodies.add(start, []string{})
+	}
+
+	var productions []Production
+	for _, head := range append(append([]string{}, nonterminals...), fresh...) {
+		for _, body := range bodies.of(head) {
+			productions = append(productions, Production{Head: head, Body: body})
+		}
+	}
+	return Grammar{Name: grammar.Name, Terminals: grammar.Terminals, Start: start, Productions: productions, Tokenizer: grammar.Tokenizer}, nil
+}
+
+/**
+productions grouped by head in the order they are added, without duplicates.
+*/
+type productionSet struct {
+	bodies map[string][][]string
+	seen   map[string]bool
+	size   int
+}
+
+func newProductionSet() *productionSet {
+	return &productionSet{bodies: make(map[string][][]string), seen: make(map[string]bool)}
+}
+
+func (set *productionSet) add(head string, body []string) {
+	key := head + "\x00" + strings.Join(body, "\x00")
+	if set.seen[key] {
+		return
+	}
+	set.seen[key] = true
+	set.bodies[head] = append(set.bodies[head], body)
+	set.size++
+}
+
+func (set *productionSet) of(head string) [][]string {
+	return set.bodies[head]
+}
+
+func (set *productionSet) replace(head string, bodies [][]string) {
+	for _, body := range set.bodies[head] {
+		delete(set.seen, head+"\x00"+strings.Join(body, "\x00"))
+		set.size--
+	}
+	set.bodies[head] = nil
+	for _, body := range bodies {
+		set.add(head, body)
+	}
+}
+
+/**
+ToPDA compiles the grammar into a nondeterministic PDA with the standard construction. The PDA pushes the start symbol,
+then in its loop state replaces a nonterminal on top of the stack by the body of one of its productions or pops a
+terminal on top of the stack matching the input, and accepts once only the end of stack marker is left.
+A left recursive grammar is first rewritten into an equivalent one without left recursion, as the PDA would otherwise
+expand it forever without reading input; the stack alphabet then also holds the nonterminals added by the rewriting.
+*/
+func (grammar *Grammar) ToPDA(id int) (PDAProcessor, error) {
+	if diagnostics := grammar.Validate(); len(diagnostics) > 0 {
+		return PDAProcessor{}, &ValidationError{Diagnostics: diagnostics}
+	}
+	if grammar.leftRecursive() {
+		rewritten, err := grammar.withoutLeftRecursion()
+		if err != nil {
+			return PDAProcessor{}, err
+		}
+		grammar = &rewritten
+	}
+
+	nonterminals := grammar.nonterminals()
+	// a nonterminal left without productions by the rewriting still appears in bodies
+	for _, production := range grammar.Productions {
+		for _, symbol := range production.Body {
+			if !findInArray(grammar.Terminals, symbol) && !findInArray(nonterminals, symbol) {
+				nonterminals = append(nonterminals, symbol)
+			}
+		}
+	}
+	stackAlphabet := append(append([]string{}, grammar.Terminals...), nonterminals...)
+
+	// end of stack marker which is not a grammar symbol
+	eos := "$"
+	for findInArray(stackAlphabet, eos) {
+		eos += "$"
+	}
+
+	const start, loop, accept = "q_start", "q_loop", "q_accept"
+	transitions := []Transition{{State: start, NextState: loop, Push: []string{grammar.Start, eos}}}
+	for _, production := range grammar.Productions {
+		transitions = append(transitions, Transition{State: loop, Pop: production.Head, NextState: loop, Push: append([]string{}, production.Body...)})
+	}
+	for _, terminal := range grammar.Terminals {
+		transitions = append(transitions, Transition{State: loop, Input: terminal, Pop: terminal, NextState: loop, Push: []string{}})
+	}
+	transitions = append(transitions, Transition{State: loop, Pop: eos, NextState: accept, Push: []string{}})
+
+	pdaProcessor := PDAProcessor{
+		ID:              id,
+		Name:            grammar.Name,
+		States:          []string{start, loop, accept},
+		InputAlphabet:   append([]string{}, grammar.Terminals...),
+		StackAlphabet:   stackAlphabet,
+		AcceptingStates: []string{accept},
+		StartState:      start,
+		Transitions:     transitions,
+		Eos:             eos,
+		Mode:            PDA_MODE_NONDETERMINISTIC,
+		Tokenizer:       grammar.Tokenizer,
+	}
+	if diagnostics := pdaProcessor.Validate(); len(diagnostics) > 0 {
+		return pdaProcessor, &ValidationError{Diagnostics: diagnostics}
+	}
+	return pdaProcessor, nil
+}
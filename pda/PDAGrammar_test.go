@@ -0,0 +1,158 @@
+package pda
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+/**
+grammar over the whitespace separated terminals from rules like "E -> E + T | T", where ε is an empty body. The head
+of the first rule is the start symbol.
+*/
+func grammarOf(terminals string, rules ...string) Grammar {
+	grammar := Grammar{Name: "test", Terminals: strings.Fields(terminals)}
+	for _, rule := range rules {
+		head, alternatives, _ := strings.Cut(rule, "->")
+		head = strings.TrimSpace(head)
+		if grammar.Start == "" {
+			grammar.Start = head
+		}
+		for _, alternative := range strings.Split(alternatives, "|") {
+			body := []string{}
+			for _, symbol := range strings.Fields(alternative) {
+				if symbol != "ε" {
+					body = append(body, symbol)
+				}
+			}
+			grammar.Productions = append(grammar.Productions, Production{Head: head, Body: body})
+		}
+	}
+	return grammar
+}
+
+/**
+the inputs of up to maxLength tokens derived from the start symbol, computed as the least fixpoint of the productions
+independently of any PDA.
+*/
+func languageOf(grammar Grammar, maxLength int) map[string]bool {
+	derived := make(map[string]map[string][]string)
+	for changed := true; changed; {
+		changed = false
+		for _, production := range grammar.Productions {
+			inputs := [][]string{{}}
+			for _, symbol := range production.Body {
+				var extended [][]string
+				for _, input := range inputs {
+					if findInArray(grammar.Terminals, symbol) {
+						if len(input) < maxLength {
+							extended = append(extended, append(append([]string{}, input...), symbol))
+						}
+						continue
+					}
+					for _, suffix := range derived[symbol] {
+						if len(input)+len(suffix) <= maxLength {
+							extended = append(extended, append(append([]string{}, input...), suffix...))
+						}
+					}
+				}
+				inputs = extended
+			}
+			for _, input := range inputs {
+				key := strings.Join(input, " ")
+				if derived[production.Head] == nil {
+					derived[production.Head] = make(map[string][]string)
+				}
+				if _, exists := derived[production.Head][key]; !exists {
+					derived[production.Head][key] = input
+					changed = true
+				}
+			}
+		}
+	}
+	language := make(map[string]bool)
+	for key := range derived[grammar.Start] {
+		language[key] = true
+	}
+	return language
+}
+
+/**
+every input of up to maxLength tokens over the alphabet, shortest first.
+*/
+func inputsUpTo(alphabet []string, maxLength int) []string {
+	inputs := []string{""}
+	level := []string{""}
+	for length := 1; length <= maxLength; length++ {
+		var next []string
+		for _, prefix := range level {
+			for _, symbol := range alphabet {
+				next = append(next, strings.TrimSpace(prefix+" "+symbol))
+			}
+		}
+		inputs = append(inputs, next...)
+		level = next
+	}
+	return inputs
+}
+
+/**
+check that the PDA accepts exactly the inputs of up to maxLength tokens in language.
+*/
+func assertAcceptsExactly(t *testing.T, name string, pdaProcessor *PDAProcessor, language map[string]bool, maxLength int) {
+	t.Helper()
+	var wrong []string
+	for _, input := range inputsUpTo(pdaProcessor.InputAlphabet, maxLength) {
+		if err := pdaProcessor.Reset(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, _, err := pdaProcessor.EvaluateInput(input); err != nil {
+			t.Fatalf("%s: %q failed with %v", name, input, err)
+		}
+		if pdaProcessor.IsAccepted() != language[input] {
+			wrong = append(wrong, input)
+		}
+	}
+	if len(wrong) > 0 {
+		sort.Strings(wrong)
+		t.Errorf("%s: %d inputs accepted or rejected wrongly, e.g. %q", name, len(wrong), wrong[0])
+	}
+}
+
+func TestGrammarToPDAAcceptsTheLanguage(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		grammar       Grammar
+		leftRecursive bool
+		maxLength     int
+	}{
+		{"balanced parentheses", grammarOf("( )", "S -> ( S ) S | ε"), false, 8},
+		{"arithmetic", grammarOf("id + * ( )", "E -> E + T | T", "T -> T * F | F", "F -> ( E ) | id"), true, 5},
+		{"indirect", grammarOf("a b c d", "A -> B a | b", "B -> A c | d | ε"), true, 6},
+		{"nullable prefix", grammarOf("a b c", "S -> N S a | b", "N -> ε | c"), true, 6},
+		{"unit cycle", grammarOf("a b", "A -> B | a", "B -> A | b B"), true, 6},
+		{"nullable start", grammarOf("x y", "L -> L x | L y y | ε"), true, 6},
+	} {
+		if test.grammar.leftRecursive() != test.leftRecursive {
+			t.Fatalf("%s: left recursive is %t, want %t", test.name, !test.leftRecursive, test.leftRecursive)
+		}
+		if test.leftRecursive {
+			rewritten, err := test.grammar.withoutLeftRecursion()
+			if err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if rewritten.leftRecursive() {
+				t.Fatalf("%s: rewritten grammar %v is still left recursive", test.name, rewritten.Productions)
+			}
+		}
+
+		pdaProcessor, err := test.grammar.ToPDA(1)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if err := pdaProcessor.Init(); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		assertAcceptsExactly(t, test.name, &pdaProcessor, languageOf(test.grammar, test.maxLength), test.maxLength)
+	}
+}
@@ -0,0 +1,129 @@
+package pda
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+RenderGraph renders the state diagram of the PDA in the given format, dot (Graphviz) or mermaid. Every transition is an edge labelled
+"input, pop → push" with ε for an empty part, the start state has an incoming arrow and accepting states are drawn
+with a double border. The transitions taken by trace are highlighted, each labelled with the numbers of the moves
+that took it, oldest move first.
+*/
+func (pdaProcessor *PDAProcessor) RenderGraph(format string, trace []TraceStep) (string, error) {
+	// moves of the trace per transition, numbered from 1
+	taken := make(map[int][]string)
+	for i, step := range trace {
+		taken[step.Transition] = append(taken[step.Transition], fmt.Sprint(i+1))
+	}
+
+	switch format {
+	case "", PDA_GRAPH_FORMAT_DOT:
+		return pdaProcessor.renderDot(taken), nil
+	case PDA_GRAPH_FORMAT_MERMAID:
+		return pdaProcessor.renderMermaid(taken), nil
+	}
+	return "", fmt.Errorf("graph format should be one of %s or %s", PDA_GRAPH_FORMAT_DOT, PDA_GRAPH_FORMAT_MERMAID)
+}
+
+func (pdaProcessor *PDAProcessor) renderDot(taken map[int][]string) string {
+	var graph strings.Builder
+	fmt.Fprintf(&graph, "digraph %s {\n", dotQuote(pdaProcessor.Name))
+	graph.WriteString("  rankdir=LR;\n")
+	graph.WriteString("  node [shape=circle];\n")
+	// invisible node the arrow to the start state comes from
+	start := dotQuote(freshName("__start", pdaProcessor.States))
+	fmt.Fprintf(&graph, "  %s [shape=none, width=0, height=0, label=\"\"];\n", start)
+	for _, state := range pdaProcessor.States {
+		var attributes []string
+		if state == pdaProcessor.StartState {
+			attributes = append(attributes, "penwidth=3")
+		}
+		if findInArray(pdaProcessor.AcceptingStates, state) {
+			attributes = append(attributes, "shape=doublecircle")
+		}
+		if len(attributes) > 0 {
+			fmt.Fprintf(&graph, "  %s [%s];\n", dotQuote(state), strings.Join(attributes, ", "))
+		} else {
+			fmt.Fprintf(&graph, "  %s;\n", dotQuote(state))
+		}
+	}
+	fmt.Fprintf(&graph, "  %s -> %s [penwidth=2];\n", start, dotQuote(pdaProcessor.StartState))
+	for index, transition := range pdaProcessor.Transitions {
+		label := transitionLabel(transition)
+		attributes := ""
+		if moves, isTaken := taken[index]; isTaken {
+			label += " (" + strings.Join(moves, ", ") + ")"
+			attributes = ", color=red, fontcolor=red, penwidth=2"
+		}
+		fmt.Fprintf(&graph, "  %s -> %s [label=%s%s];\n", dotQuote(transition.State), dotQuote(transition.NextState), dotQuote(label), attributes)
+	}
+	graph.WriteString("}\n")
+	return graph.String()
+}
+
+func (pdaProcessor *PDAProcessor) renderMermaid(taken map[int][]string) string {
+	// mermaid node ids can not hold arbitrary state names, states are numbered in the order they are declared
+	ids := make(map[string]string)
+	for i, state := range pdaProcessor.States {
+		ids[state] = fmt.Sprintf("s%d", i)
+	}
+
+	var graph strings.Builder
+	graph.WriteString("flowchart LR\n")
+	graph.WriteString("  start_[\" \"] --> " + ids[pdaProcessor.StartState] + "\n")
+	for _, state := range pdaProcessor.States {
+		if findInArray(pdaProcessor.AcceptingStates, state) {
+			fmt.Fprintf(&graph, "  %s(((%s)))\n", ids[state], mermaidQuote(state))
+		} else {
+			fmt.Fprintf(&graph, "  %s((%s))\n", ids[state], mermaidQuote(state))
+		}
+	}
+	// edges are numbered by mermaid in the order they are declared, the arrow to the start state is the first one
+	var highlighted []string
+	for index, transition := range pdaProcessor.Transitions {
+		label := transitionLabel(transition)
+		if moves, isTaken := taken[index]; isTaken {
+			label += " (" + strings.Join(moves, ", ") + ")"
+			highlighted = append(highlighted, fmt.Sprint(index+1))
+		}
+		fmt.Fprintf(&graph, "  %s -->|%s| %s\n", ids[transition.State], mermaidQuote(label), ids[transition.NextState])
+	}
+	graph.WriteString("  style start_ fill:none,stroke:none\n")
+	graph.WriteString("  style " + ids[pdaProcessor.StartState] + " stroke-width:3px\n")
+	if len(highlighted) > 0 {
+		graph.WriteString("  linkStyle " + strings.Join(highlighted, ",") + " stroke:red,stroke-width:3px\n")
+	}
+	return graph.String()
+}
+
+/**
+label of a transition edge, "input, pop → push" with the pushed symbols top first, ε for an empty part and * for a
+transition popping any top, followed by "/ output" for a transition writing output.
+*/
+func transitionLabel(transition Transition) string {
+	epsilonIfEmpty := func(value string) string {
+		if value == "" {
+			return "ε"
+		}
+		return value
+	}
+	pop := epsilonIfEmpty(transition.Pop)
+	if transition.PopAny {
+		pop = "*"
+	}
+	label := fmt.Sprintf("%s, %s → %s", epsilonIfEmpty(transition.Input), pop, epsilonIfEmpty(strings.Join(transition.Push, " ")))
+	if transition.Output != "" {
+		label += " / " + transition.Output
+	}
+	return label
+}
+
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func mermaidQuote(value string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(value) + `"`
+}
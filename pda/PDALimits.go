@@ -0,0 +1,55 @@
+package pda
+
+import (
+	"fmt"
+)
+
+/**
+LimitError is the error returned when a session runs into one of the limits of its PDA, Limit is the name of the specification field.
+Position is the position of the token being consumed, -1 before the first one.
+*/
+type LimitError struct {
+	Limit    string `json:"limit"`
+	Value    int    `json:"value"`
+	Position int    `json:"position"`
+}
+
+func (limitError *LimitError) Error() string {
+	return fmt.Sprintf("PDA exceeded its %s limit of %d at position %d", limitError.Limit, limitError.Value, limitError.Position)
+}
+
+/**
+count one move of the PDA against the per token and the total step budget.
+*/
+func (pdaProcessor *PDAProcessor) countStep(position int) error {
+	pdaProcessor.tokenSteps++
+	pdaProcessor.Steps++
+	if pdaProcessor.tokenSteps > pdaProcessor.maxStepsPerToken() {
+		return &LimitError{Limit: "max_steps_per_token", Value: pdaProcessor.maxStepsPerToken(), Position: position}
+	}
+	if pdaProcessor.MaxTotalSteps > 0 && pdaProcessor.Steps > pdaProcessor.MaxTotalSteps {
+		return &LimitError{Limit: "max_total_steps", Value: pdaProcessor.MaxTotalSteps, Position: position}
+	}
+	return nil
+}
+
+func (pdaProcessor *PDAProcessor) checkStackDepth(stack *PDAStack, position int) error {
+	if stack.Len() > pdaProcessor.maxStackDepth() {
+		return &LimitError{Limit: "max_stack_depth", Value: pdaProcessor.maxStackDepth(), Position: position}
+	}
+	return nil
+}
+
+func (pdaProcessor *PDAProcessor) maxStackDepth() int {
+	if pdaProcessor.MaxStackDepth > 0 {
+		return pdaProcessor.MaxStackDepth
+	}
+	return PDA_MAX_STACK_DEPTH
+}
+
+func (pdaProcessor *PDAProcessor) maxStepsPerToken() int {
+	if pdaProcessor.MaxStepsPerToken > 0 {
+		return pdaProcessor.MaxStepsPerToken
+	}
+	return PDA_MAX_STEPS_PER_TOKEN
+}
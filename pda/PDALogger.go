@@ -0,0 +1,105 @@
+package pda
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+/**
+PDA_LOG_LEVEL_TRACE is the log level below debug for the evaluation of every single transition, it is only enabled on request as it is logged per token.
+*/
+const PDA_LOG_LEVEL_TRACE = slog.LevelDebug - 4
+
+var pdaLogLevel = new(slog.LevelVar)
+
+/**
+logger shared by the server and the driver, PDAs and sessions derive their own logger from it with their ids as fields.
+*/
+var pdaLogger = newLogger(PDA_LOG_FORMAT_TEXT)
+
+/**
+DefaultLogger returns the logger shared by the server and the driver.
+*/
+func DefaultLogger() *slog.Logger {
+	return pdaLogger
+}
+
+/**
+ConfigureLogging sets verbosity and format of the logger from the given values, as read from PDA_LOG_LEVEL and PDA_LOG_FORMAT.
+Empty values keep the defaults, info level and text format.
+*/
+func ConfigureLogging(level string, format string) error {
+	if level != "" {
+		parsed, err := parseLogLevel(level)
+		if err != nil {
+			return err
+		}
+		pdaLogLevel.Set(parsed)
+	}
+
+	switch format {
+	case "", PDA_LOG_FORMAT_TEXT, PDA_LOG_FORMAT_JSON:
+		if format != "" {
+			pdaLogger = newLogger(format)
+		}
+	default:
+		return fmt.Errorf("unknown log format %q, should be one of text or json", format)
+	}
+	return nil
+}
+
+func newLogger(format string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level: pdaLogLevel,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.LevelKey && attr.Value.Any() == PDA_LOG_LEVEL_TRACE {
+				return slog.String(slog.LevelKey, "TRACE")
+			}
+			return attr
+		},
+	}
+	if format == PDA_LOG_FORMAT_JSON {
+		return slog.New(slog.NewJSONHandler(os.Stderr, options))
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, options))
+}
+
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "trace":
+		return PDA_LOG_LEVEL_TRACE, nil
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q, should be one of trace, debug, info, warn or error", level)
+}
+
+/**
+Logger returns the logger of the PDA, it carries the PDA id and the session id once the PDA is bound to a session.
+*/
+func (pdaProcessor *PDAProcessor) Logger() *slog.Logger {
+	if pdaProcessor.sessionLogger == nil {
+		pdaProcessor.sessionLogger = pdaLogger.With("pda_id", pdaProcessor.ID)
+	}
+	return pdaProcessor.sessionLogger
+}
+
+/**
+BindSession adds the session id to the logs of the PDA.
+*/
+func (pdaProcessor *PDAProcessor) BindSession(sessionId string) {
+	pdaProcessor.sessionLogger = pdaLogger.With("pda_id", pdaProcessor.ID, "session_id", sessionId)
+}
+
+func (pdaProcessor *PDAProcessor) tracing() bool {
+	return pdaProcessor.Logger().Enabled(context.Background(), PDA_LOG_LEVEL_TRACE)
+}
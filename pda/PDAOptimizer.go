@@ -0,0 +1,186 @@
+package pda
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+/**
+OptimizationReport lists what the optimizer removed from a specification. Unreachable states can not be reached from the start state, dead
+states are reached by no accepted input. Removed transitions keep their index in the original specification.
+*/
+type OptimizationReport struct {
+	UnreachableStates  []string            `json:"unreachable_states"`
+	DeadStates         []string            `json:"dead_states"`
+	RemovedTransitions []RemovedTransition `json:"removed_transitions"`
+}
+
+/**
+RemovedTransition is a transition dropped by the optimizer, Index is its position in the original specification.
+*/
+type RemovedTransition struct {
+	Index      int        `json:"index"`
+	Transition Transition `json:"transition"`
+	Reason     string     `json:"reason"`
+}
+
+/**
+Optimize returns a copy of the specification without the states and transitions which can not change whether an input is
+accepted. States not reachable from the start state and states from which no accepting state can be reached are
+removed along with their transitions. A nondeterministic PDA, or a deterministic one without conflicting transitions,
+also loses every transition no accepted input takes, as found by the grammar of the PDA. A deterministic PDA with
+conflicts keeps the transitions into dead states, taking one of them rejects the input where a later transition might
+have led to acceptance, and instead loses the transitions which always come after a transition applying to the same
+configurations. The language of the PDA stays the same, it may only reject an input at an earlier token.
+*/
+func (pdaProcessor *PDAProcessor) Optimize() (PDAProcessor, OptimizationReport, error) {
+	report := OptimizationReport{UnreachableStates: []string{}, DeadStates: []string{}, RemovedTransitions: []RemovedTransition{}}
+
+	// work on a copy of the specification only, leaving out the runtime state of the PDA
+	var optimized PDAProcessor
+	specJson, err := json.Marshal(pdaProcessor)
+	if err != nil {
+		return optimized, report, err
+	}
+	if err := json.Unmarshal(specJson, &optimized); err != nil {
+		return optimized, report, err
+	}
+
+	reachable := pdaProcessor.reachableStates()
+	live := pdaProcessor.liveStates()
+	exact := pdaProcessor.isNondeterministic() || pdaProcessor.AnalyzeDeterminism().Deterministic
+
+	// transitions taking part in an accepted input, nil when the grammar is too large to tell
+	var useful []bool
+	if exact {
+		if start, productions, origins, err := pdaProcessor.tripleProductions(PDA_GRAMMAR_MAX_PRODUCTIONS); err == nil {
+			useful = make([]bool, len(pdaProcessor.Transitions))
+			for _, i := range usefulProductions(productions, start, pdaProcessor.InputAlphabet) {
+				if origins[i] >= 0 {
+					useful[origins[i]] = true
+				}
+			}
+		}
+	}
+
+	optimized.Transitions = []Transition{}
+	kept := map[string]bool{pdaProcessor.StartState: true}
+	for index, transition := range pdaProcessor.Transitions {
+		var reason string
+		switch {
+		case !reachable[transition.State]:
+			reason = fmt.Sprintf("state %q is not reachable from the start state", transition.State)
+		case !live[transition.State]:
+			reason = fmt.Sprintf("no accepting state can be reached from state %q", transition.State)
+		case exact && !live[transition.NextState]:
+			reason = fmt.Sprintf("no accepting state can be reached from state %q", transition.NextState)
+		case useful != nil && !useful[index]:
+			reason = "no accepted input takes the transition"
+		case !exact:
+			if shadowing := pdaProcessor.shadowingTransition(index); shadowing >= 0 {
+				reason = fmt.Sprintf("transitions[%d] applies to every configuration it applies to and comes first", shadowing)
+			}
+		}
+		if reason != "" {
+			report.RemovedTransitions = append(report.RemovedTransitions, RemovedTransition{Index: index, Transition: transition, Reason: reason})
+			continue
+		}
+		optimized.Transitions = append(optimized.Transitions, transition)
+		kept[transition.State] = true
+		kept[transition.NextState] = true
+	}
+
+	// an accepting state is left when acceptance needs one, even if the PDA accepts nothing
+	if pdaProcessor.Acceptance != PDA_ACCEPTANCE_EMPTY_STACK && len(pdaProcessor.AcceptingStates) > 0 {
+		keepsAccepting := false
+		for _, state := range pdaProcessor.AcceptingStates {
+			keepsAccepting = keepsAccepting || kept[state]
+		}
+		if !keepsAccepting {
+			kept[pdaProcessor.AcceptingStates[0]] = true
+		}
+	}
+
+	optimized.States = []string{}
+	for _, state := range pdaProcessor.States {
+		switch {
+		case kept[state]:
+			optimized.States = append(optimized.States, state)
+		case !reachable[state]:
+			report.UnreachableStates = append(report.UnreachableStates, state)
+		default:
+			report.DeadStates = append(report.DeadStates, state)
+		}
+	}
+	optimized.AcceptingStates = []string{}
+	for _, state := range pdaProcessor.AcceptingStates {
+		if kept[state] {
+			optimized.AcceptingStates = append(optimized.AcceptingStates, state)
+		}
+	}
+	return optimized, report, nil
+}
+
+/**
+return the states reachable from the start state through the transitions, regardless of the stack.
+*/
+func (pdaProcessor *PDAProcessor) reachableStates() map[string]bool {
+	next := make(map[string][]string)
+	for _, transition := range pdaProcessor.Transitions {
+		next[transition.State] = append(next[transition.State], transition.NextState)
+	}
+	return closeStates([]string{pdaProcessor.StartState}, next)
+}
+
+/**
+return the states from which an accepting state can be reached through the transitions, regardless of the stack.
+Every state can accept by empty stack.
+*/
+func (pdaProcessor *PDAProcessor) liveStates() map[string]bool {
+	accepting := pdaProcessor.AcceptingStates
+	if pdaProcessor.Acceptance == PDA_ACCEPTANCE_EMPTY_STACK {
+		accepting = pdaProcessor.States
+	}
+	previous := make(map[string][]string)
+	for _, transition := range pdaProcessor.Transitions {
+		previous[transition.NextState] = append(previous[transition.NextState], transition.State)
+	}
+	return closeStates(accepting, previous)
+}
+
+func closeStates(states []string, edges map[string][]string) map[string]bool {
+	closed := make(map[string]bool)
+	pending := append([]string{}, states...)
+	for _, state := range pending {
+		closed[state] = true
+	}
+	for len(pending) > 0 {
+		state := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, next := range edges[state] {
+			if !closed[next] {
+				closed[next] = true
+				pending = append(pending, next)
+			}
+		}
+	}
+	return closed
+}
+
+/**
+return the index of an earlier transition which applies whenever the transition at index does, -1 if there is none.
+A deterministic PDA always takes the earlier one, so the transition at index never fires.
+*/
+func (pdaProcessor *PDAProcessor) shadowingTransition(index int) int {
+	transition := pdaProcessor.Transitions[index]
+	for i, earlier := range pdaProcessor.Transitions[:index] {
+		if earlier.State != transition.State || earlier.Input != transition.Input {
+			continue
+		}
+		// popping any top applies whenever the stack is not empty, which every transition inspecting the stack needs
+		if !earlier.popsStack() || (earlier.PopAny && transition.popsStack()) || (earlier.Pop != "" && earlier.Pop == transition.Pop) {
+			return i
+		}
+	}
+	return -1
+}
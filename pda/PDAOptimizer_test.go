@@ -0,0 +1,84 @@
+package pda
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+/**
+optimize the PDA and check that the optimized PDA accepts the same inputs of up to maxLength tokens.
+*/
+func assertOptimizationKeepsLanguage(t *testing.T, name string, pdaProcessor *PDAProcessor, maxLength int) (PDAProcessor, OptimizationReport) {
+	t.Helper()
+	accepted := acceptedInputs(t, pdaProcessor, maxLength)
+	optimized, report, err := pdaProcessor.Optimize()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if err := optimized.Init(); err != nil {
+		t.Fatalf("%s: optimized specification is invalid: %v", name, err)
+	}
+	assertAcceptsExactly(t, name, &optimized, accepted, maxLength)
+	return optimized, report
+}
+
+func removedIndexes(report OptimizationReport) []int {
+	indexes := []int{}
+	for _, removed := range report.RemovedTransitions {
+		indexes = append(indexes, removed.Index)
+	}
+	return indexes
+}
+
+func TestOptimizeRemovesUnreachableAndDeadStates(t *testing.T) {
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f", "d", "u"`, evenPalindromeTransitions+`,
+		{"state": "q1", "input": "a", "next_state": "d"},
+		{"state": "d", "input": "b", "next_state": "d"},
+		{"state": "u", "input": "b", "next_state": "f"},
+		["q2", "a", "X", "q2", null]`, PDA_MODE_NONDETERMINISTIC))
+
+	optimized, report := assertOptimizationKeepsLanguage(t, "palindromes", pdaProcessor, 8)
+	if !reflect.DeepEqual(report.UnreachableStates, []string{"u"}) || !reflect.DeepEqual(report.DeadStates, []string{"d"}) {
+		t.Errorf("got unreachable %v and dead %v, want [u] and [d]", report.UnreachableStates, report.DeadStates)
+	}
+	// X is never pushed, so popping it is taken by no accepted input
+	if indexes := removedIndexes(report); !reflect.DeepEqual(indexes, []int{7, 8, 9, 10}) {
+		t.Errorf("got removed transitions %v, want [7 8 9 10]", indexes)
+	}
+	if !reflect.DeepEqual(optimized.States, []string{"q0", "q1", "q2", "f"}) || len(optimized.Transitions) != 7 {
+		t.Errorf("got states %v and %d transitions", optimized.States, len(optimized.Transitions))
+	}
+}
+
+func TestOptimizeRemovesShadowedTransitions(t *testing.T) {
+	// the deterministic PDA always takes q0 a -> q1 and rejects, so q0 a -> f never fires
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f"`, `
+		{"state": "q0", "input": "a", "next_state": "q1"},
+		{"state": "q0", "input": "a", "next_state": "f"},
+		{"state": "q0", "input": "b", "next_state": "q2"},
+		{"state": "q2", "input": "a", "next_state": "f"}`, PDA_MODE_DETERMINISTIC))
+
+	_, report := assertOptimizationKeepsLanguage(t, "shadowed", pdaProcessor, 4)
+	if indexes := removedIndexes(report); !reflect.DeepEqual(indexes, []int{1}) {
+		t.Errorf("got removed transitions %v, want [1]", indexes)
+	}
+	if len(report.UnreachableStates) != 0 || len(report.DeadStates) != 0 {
+		t.Errorf("got unreachable %v and dead %v, want none", report.UnreachableStates, report.DeadStates)
+	}
+}
+
+func TestOptimizeKeepsLanguageOfShippedSpecifications(t *testing.T) {
+	files, err := filepath.Glob("../PDAFiles/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no specifications found: %v", err)
+	}
+	for _, file := range files {
+		specification, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertOptimizationKeepsLanguage(t, file, loadSpecification(t, specification), 8)
+	}
+}
@@ -0,0 +1,809 @@
+package pda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+PDAProcessor is a PDA specification, as read from its JSON file, together with the runtime state of its evaluation.
+*/
+type PDAProcessor struct {
+	ID                        int             `json:"ID"`
+	Name                      string          `json:"name"`
+	States                    []string        `json:"states"`
+	InputAlphabet             []string        `json:"input_alphabet"`
+	StackAlphabet             []string        `json:"stack_alphabet"`
+	AcceptingStates           []string        `json:"accepting_states"`
+	StartState                string          `json:"start_state"`
+	Transitions               []Transition    `json:"transitions"`
+	Eos                       string          `json:"eos"`
+	Mode                      string          `json:"mode,omitempty"`
+	Acceptance                string          `json:"acceptance,omitempty"`
+	Tokenizer                 *Tokenizer      `json:"tokenizer,omitempty"`
+	OutputSeparator           string          `json:"output_separator,omitempty"`
+	MaxPendingTokens          int             `json:"max_pending_tokens,omitempty"`
+	MaxCheckpoints            int             `json:"max_checkpoints,omitempty"`
+	MaxStackDepth             int             `json:"max_stack_depth,omitempty"`
+	MaxStepsPerToken          int             `json:"max_steps_per_token,omitempty"`
+	MaxTotalSteps             int             `json:"max_total_steps,omitempty"`
+	Stack                     *PDAStack       `json:"-"`
+	CurrentState              string          `json:"-"`
+	CurrentStackTop           string          `json:"-"`
+	PdaClock                  int             `json:"-"`
+	PendingTokenQueue         map[int]string  `json:"-"`
+	LastConsumedPosition      int             `json:"-"`
+	PDAFailedInLastEvaluation bool            `json:"-"`
+	EOSPresentedAtPosition    int             `json:"-"`
+	Configurations            []Configuration `json:"-"`
+	Steps                     int             `json:"-"`
+	transitionIndex           map[transitionKey][]int
+	trace                     *traceNode
+	checkpoints               []checkpoint
+	tokenSteps                int
+	lexerPatterns             []*regexp.Regexp
+	sessionLogger             *slog.Logger
+}
+
+/**
+Configuration is the instantaneous description of one branch of the PDA: control state together with its stack contents.
+*/
+type Configuration struct {
+	State string    `json:"state"`
+	Stack *PDAStack `json:"stack"`
+	trace *traceNode
+}
+
+/**
+transitions are indexed by the state, input and stack top they apply to.
+*/
+type transitionKey struct {
+	state string
+	input string
+	pop   string
+}
+
+/**
+Open loads and parses/processes spec as the JSON specification file of a PDA. Return True on success.
+*/
+func (pdaProcessor *PDAProcessor) Open(specFilePath string) (bool, error) {
+	pdaLogger.Debug("opening PDA specification", "file", specFilePath)
+
+	file, err := ioutil.ReadFile(specFilePath)
+	if err != nil {
+		return false, fmt.Errorf("couldn't open specification file: %s, %v", specFilePath, err)
+	}
+
+	if err := pdaProcessor.Load(file); err != nil {
+		return false, err
+	}
+	pdaProcessor.Logger().Info("loaded PDA", "file", specFilePath, "name", pdaProcessor.Name)
+
+	return true, nil
+}
+
+/**
+Load parses the JSON specification of a PDA, validates it and puts the PDA at its start state. A specification with
+problems fails with a ValidationError listing all of them.
+*/
+func (pdaProcessor *PDAProcessor) Load(specJson []byte) error {
+	err := json.Unmarshal(specJson, &pdaProcessor)
+	if err != nil {
+		return fmt.Errorf("couldn't unmashal specification from file to PDAProcessor object, %v", err)
+	}
+
+	return pdaProcessor.Init()
+}
+
+/**
+Init validates the specification set on the PDA and puts the PDA at its start state, as Load does once it parsed the
+specification. A specification with problems fails with a ValidationError, one whose epsilon moves from the start
+state run into a limit fails with the LimitError, such a PDA can never be used.
+*/
+func (pdaProcessor *PDAProcessor) Init() error {
+	pdaProcessor.PdaClock = 2
+
+	diagnostics := pdaProcessor.Validate()
+	if len(diagnostics) > 0 {
+		return &ValidationError{Diagnostics: diagnostics}
+	}
+
+	// index transitions and initialize all the struct parameters
+	pdaProcessor.compileTransitions()
+	if err := pdaProcessor.reset(false); err != nil {
+		return err
+	}
+	pdaProcessor.PdaClock++
+
+	return nil
+}
+
+/**
+Clone returns a copy of the PDA which shares nothing the copy or the original could change, so that either one can be
+run or modified without affecting the other. Stacks and traces are immutable and stay shared.
+*/
+func (pdaProcessor *PDAProcessor) Clone() PDAProcessor {
+	clone := *pdaProcessor
+	clone.States = cloneStrings(pdaProcessor.States)
+	clone.InputAlphabet = cloneStrings(pdaProcessor.InputAlphabet)
+	clone.StackAlphabet = cloneStrings(pdaProcessor.StackAlphabet)
+	clone.AcceptingStates = cloneStrings(pdaProcessor.AcceptingStates)
+	if pdaProcessor.Transitions != nil {
+		clone.Transitions = make([]Transition, len(pdaProcessor.Transitions))
+		for i, transition := range pdaProcessor.Transitions {
+			transition.Push = cloneStrings(transition.Push)
+			clone.Transitions[i] = transition
+		}
+	}
+	if pdaProcessor.Tokenizer != nil {
+		tokenizer := *pdaProcessor.Tokenizer
+		if tokenizer.Rules != nil {
+			tokenizer.Rules = append([]LexerRule{}, tokenizer.Rules...)
+		}
+		clone.Tokenizer = &tokenizer
+	}
+	clone.PendingTokenQueue = cloneQueue(pdaProcessor.PendingTokenQueue)
+	clone.Configurations = cloneConfigurations(pdaProcessor.Configurations)
+	if pdaProcessor.transitionIndex != nil {
+		clone.transitionIndex = make(map[transitionKey][]int, len(pdaProcessor.transitionIndex))
+		for key, indexes := range pdaProcessor.transitionIndex {
+			clone.transitionIndex[key] = append([]int{}, indexes...)
+		}
+	}
+	if pdaProcessor.checkpoints != nil {
+		clone.checkpoints = make([]checkpoint, len(pdaProcessor.checkpoints))
+		for i, checkpoint := range pdaProcessor.checkpoints {
+			checkpoint.configurations = cloneConfigurations(checkpoint.configurations)
+			checkpoint.pendingTokenQueue = cloneQueue(checkpoint.pendingTokenQueue)
+			clone.checkpoints[i] = checkpoint
+		}
+	}
+	if pdaProcessor.lexerPatterns != nil {
+		// compiled patterns are safe for concurrent use, only the slice is copied
+		clone.lexerPatterns = append([]*regexp.Regexp{}, pdaProcessor.lexerPatterns...)
+	}
+	return clone
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+func cloneQueue(queue map[int]string) map[int]string {
+	if queue == nil {
+		return nil
+	}
+	clone := make(map[int]string, len(queue))
+	for position, token := range queue {
+		clone[position] = token
+	}
+	return clone
+}
+
+func cloneConfigurations(configurations []Configuration) []Configuration {
+	if configurations == nil {
+		return nil
+	}
+	return append([]Configuration{}, configurations...)
+}
+
+/**
+Reset puts the PDA back at its start state with an empty stack, the pending tokens and checkpoints are dropped.
+Fails with a LimitError when the epsilon moves from the start state run into a limit, the PDA is then left failed.
+*/
+func (pdaProcessor *PDAProcessor) Reset() error {
+	return pdaProcessor.reset(true)
+}
+
+/**
+initialize the PDA to be at its start state with an empty stack, isReset logs it as a reset requested by the client.
+*/
+func (pdaProcessor *PDAProcessor) reset(isReset bool) error {
+	pdaProcessor.Steps = 0
+	pdaProcessor.tokenSteps = 0
+	configurations, err := pdaProcessor.epsilonClosure([]Configuration{{State: pdaProcessor.StartState}}, -1)
+	pdaProcessor.Configurations = configurations
+	pdaProcessor.syncCurrentConfiguration()
+	pdaProcessor.PendingTokenQueue = make(map[int]string)
+	pdaProcessor.EOSPresentedAtPosition = -1
+	pdaProcessor.LastConsumedPosition = -1
+	pdaProcessor.PDAFailedInLastEvaluation = err != nil
+	pdaProcessor.checkpoints = nil
+
+	if err != nil {
+		pdaProcessor.Logger().Warn("reset PDA ran into a limit", "error", err)
+		return err
+	}
+	if isReset {
+		pdaProcessor.Logger().Info("reset PDA", "state", pdaProcessor.CurrentState)
+	}
+	return nil
+}
+
+/**
+EvaluateInput splits tokenStream with the tokenizer of the PDA and presents the tokens one at a time. The PDA consumes each token,
+takes appropriate transition(s), and returns the trace of the transitions taken, with the lexeme each token was read from
+and the output each move wrote, together with the output symbols written, as returned by OutputSymbols.
+*/
+func (pdaProcessor *PDAProcessor) EvaluateInput(tokenStream string) ([]TraceStep, []string, error) {
+	tokens, err := pdaProcessor.Tokenize(tokenStream)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for index, token := range tokens {
+		err := validateInput(pdaProcessor, token.Symbol)
+		if err != nil {
+			return nil, nil, err
+		}
+		transitionTaken, err := pdaProcessor.Step(index+1, token.Symbol)
+		if err != nil {
+			pdaProcessor.PDAFailedInLastEvaluation = true
+			return withLexemes(pdaProcessor.TransitionsTaken(), tokens), pdaProcessor.OutputSymbols(), err
+		}
+
+		if len(transitionTaken) == 0 {
+			pdaProcessor.Logger().Debug("no transition for input", "token", token.Symbol, "lexeme", token.Lexeme, "position", index+1, "offset", token.Offset)
+			pdaProcessor.PDAFailedInLastEvaluation = true
+			break
+		}
+	}
+
+	if len(tokens) > 0 && !pdaProcessor.PDAFailedInLastEvaluation && pdaProcessor.eos() {
+		pdaProcessor.Logger().Debug("end of input reached", "clock", pdaProcessor.PdaClock)
+	}
+
+	return withLexemes(pdaProcessor.TransitionsTaken(), tokens), pdaProcessor.OutputSymbols(), nil
+}
+
+/**
+fill in the lexeme of the token consumed by each move, epsilon moves keep an empty lexeme.
+*/
+func withLexemes(steps []TraceStep, tokens []Token) []TraceStep {
+	for i, step := range steps {
+		if step.Token != "" && step.Position >= 0 && step.Position < len(tokens) {
+			steps[i].Lexeme = tokens[step.Position].Lexeme
+		}
+	}
+	return steps
+}
+
+/**
+TransitionsTaken returns the moves which led to the current configuration, oldest first.
+*/
+func (pdaProcessor *PDAProcessor) TransitionsTaken() []TraceStep {
+	return pdaProcessor.trace.steps(pdaProcessor.Transitions)
+}
+
+/**
+Output returns the output written by the moves which led to the current configuration, its symbols joined with the
+output_separator of the specification, none by default.
+*/
+func (pdaProcessor *PDAProcessor) Output() string {
+	return strings.Join(pdaProcessor.OutputSymbols(), pdaProcessor.OutputSeparator)
+}
+
+/**
+OutputSymbols returns the output symbols written by the moves which led to the current configuration, in the order
+written, so that the caller can join them as it needs.
+*/
+func (pdaProcessor *PDAProcessor) OutputSymbols() []string {
+	return pdaProcessor.trace.output(pdaProcessor.Transitions)
+}
+
+/**
+announce the end of input token-stream to the PDA.
+*/
+func (pdaProcessor *PDAProcessor) eos() bool {
+	for _, configuration := range pdaProcessor.Configurations {
+		if configuration.Stack.Len() > 0 && configuration.Stack.Top() == pdaProcessor.Eos {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+IsAccepted returns True if PDA is currently accepting according to its acceptance mode; False otherwise.
+By default (acceptance "both") PDA has to be at an accepting state with empty stack, "final_state" only checks
+the state and "empty_stack" only checks the stack. The input is accepted when any live configuration, including those reached through epsilon moves, is accepting.
+*/
+func (pdaProcessor *PDAProcessor) IsAccepted() bool {
+	pdaProcessor.Logger().Debug("is accepted", "state", pdaProcessor.CurrentState)
+	pdaProcessor.PdaClock++
+	if pdaProcessor.PDAFailedInLastEvaluation {
+		return false
+	}
+	for _, configuration := range pdaProcessor.Configurations {
+		if isAcceptingConfiguration(pdaProcessor, configuration) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/**
+Peek returns up to k stack tokens from the top of the stack (default k = 1), bottom to top, without modifying the stack.
+*/
+func (pdaProcessor *PDAProcessor) Peek(k int) []string {
+	pdaProcessor.Logger().Debug("peek", "k", k, "stack", pdaProcessor.Stack)
+	if k <= 0 {
+		k = 1
+	}
+	pdaProcessor.PdaClock++
+
+	return pdaProcessor.Stack.Peek(k)
+}
+
+/**
+State returns the current state of the PDA’s control.
+*/
+func (pdaProcessor *PDAProcessor) State() string {
+	pdaProcessor.Logger().Debug("current state", "state", pdaProcessor.CurrentState)
+
+	return pdaProcessor.CurrentState
+}
+
+/**
+Close garbage-collects/returns any (re-usable) resources used by the PDA.
+*/
+func (pdaProcessor *PDAProcessor) Close() {
+	pdaProcessor.Logger().Info("closed PDA")
+}
+
+/**
+Put presents token at position. Tokens are consumed in position order, a token ahead of the next expected position
+is queued until the gap is filled. Returns the current state once the token was consumed, "" when it was queued.
+*/
+func (pdaProcessor *PDAProcessor) Put(position int, token string) (string, error) {
+	if pdaProcessor.PDAFailedInLastEvaluation {
+		return "", errors.New("PDA Evaluation failed while consuming last valid token, Please reset PDA before using it")
+	}
+	if pdaProcessor.EOSPresentedAtPosition != -1 && pdaProcessor.EOSPresentedAtPosition < position {
+		return "", errors.New("EOS is already presented before this position so PDA can not accept more tokens")
+	}
+	if position < 0 {
+		return "", errors.New("position should be a non negative integer")
+	}
+	if position < pdaProcessor.LastConsumedPosition {
+		return "", errors.New("PDA already consumed all the tokens up to position " + strconv.Itoa(pdaProcessor.LastConsumedPosition-1))
+	}
+
+	var transitionTaken = ""
+	var err error = nil
+	pdaProcessor.Logger().Debug("token presented", "token", token, "position", position)
+	if position == 0 || position == pdaProcessor.LastConsumedPosition {
+		transitionTaken, err = consumeToken(pdaProcessor, position, token, false)
+		if err != nil {
+			return transitionTaken, err
+		}
+	} else {
+		if _, queued := pdaProcessor.PendingTokenQueue[position]; !queued {
+			if len(pdaProcessor.PendingTokenQueue) >= pdaProcessor.maxPendingTokens() {
+				return "", fmt.Errorf("pending token queue is full, at most %d tokens can wait for earlier positions", pdaProcessor.maxPendingTokens())
+			}
+			pdaProcessor.Logger().Debug("token queued", "token", token, "position", position)
+
+			// push to pending queue at given position
+			pdaProcessor.PendingTokenQueue[position] = token
+		} else {
+			pdaProcessor.Logger().Debug("token already queued for position", "position", position)
+		}
+		printLog(pdaProcessor)
+	}
+
+	return transitionTaken, nil
+}
+
+func consumeToken(pdaProcessor *PDAProcessor, position int, token string, processingPendingQueue bool) (string, error) {
+	var transitionTaken = ""
+
+	// consume token directly
+	pdaProcessor.Logger().Debug("consuming token", "token", token, "position", position)
+	pdaProcessor.saveCheckpoint(position)
+	transitionTaken, err := pdaProcessor.Step(position+1, token)
+	printLog(pdaProcessor)
+
+	if err != nil {
+		pdaProcessor.PDAFailedInLastEvaluation = true
+		return "", err
+	} else if len(transitionTaken) == 0 {
+		pdaProcessor.PDAFailedInLastEvaluation = true
+		return "", errors.New(fmt.Sprintf("PDA failed to make transition for input %q at position: %d", token, position))
+	} else {
+		// process if current toke was at EOS position
+		if pdaProcessor.EOSPresentedAtPosition != -1 && pdaProcessor.EOSPresentedAtPosition == position {
+			// reached EOS
+			transitionTaken, err := reachedEOS(pdaProcessor)
+			if err != nil {
+				return transitionTaken, err
+			}
+		} else if !processingPendingQueue {
+			// as this call is for actual token presented from user and it is accepted as well,
+			// process subsequent items from pending queue if any
+			processedAtLeastOne := false
+			for index := position + 1; ; index++ {
+				t, queued := pdaProcessor.PendingTokenQueue[index]
+				if !queued {
+					break
+				}
+				// clear token consumed
+				delete(pdaProcessor.PendingTokenQueue, index)
+				_, err := consumeToken(pdaProcessor, index, t, true)
+				if err != nil {
+					return transitionTaken, err
+				}
+				processedAtLeastOne = true
+			}
+
+			if processedAtLeastOne {
+				pdaProcessor.Logger().Debug("consumed queued tokens", "last_consumed_position", pdaProcessor.LastConsumedPosition-1)
+			}
+		}
+	}
+
+	return transitionTaken, nil
+}
+
+func reachedEOS(pdaProcessor *PDAProcessor) (string, error) {
+	// epsilon moves are already followed after every consumed token, so the final moves on EOS are part of the live configurations
+	for _, configuration := range pdaProcessor.Configurations {
+		if isAcceptingConfiguration(pdaProcessor, configuration) {
+			return pdaProcessor.CurrentState, nil
+		}
+	}
+
+	pdaProcessor.PDAFailedInLastEvaluation = true
+	return "", errors.New(fmt.Sprintf("PDA reached presented EOS at position %d but no live configuration is accepting\n", pdaProcessor.EOSPresentedAtPosition))
+}
+
+/**
+log the status of the PDA after it was presented something, the status is only built when debug logging is enabled.
+*/
+func printLog(pdaProcessor *PDAProcessor) {
+	logger := pdaProcessor.Logger()
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	logger.Debug("status",
+		"state", pdaProcessor.CurrentState,
+		"stack", pdaProcessor.Stack,
+		"last_consumed_position", pdaProcessor.LastConsumedPosition-1,
+		"pending_queue", orderedTokens(pdaProcessor.PendingTokenQueue),
+		"configurations", len(pdaProcessor.Configurations),
+		"clock", pdaProcessor.PdaClock)
+}
+
+/**
+Step consumes token at position by moving every live configuration (only the first one that can move in deterministic mode)
+and following epsilon moves afterwards. Returns the current state after the move or "" if no transition applies.
+A move running into one of the limits of the PDA fails with a LimitError and leaves the configurations unchanged.
+*/
+func (pdaProcessor *PDAProcessor) Step(position int, token string) (string, error) {
+	pdaProcessor.PdaClock++
+	pdaProcessor.tokenSteps = 0
+
+	var nextConfigurations []Configuration
+	for _, configuration := range pdaProcessor.Configurations {
+		successors, err := pdaProcessor.successors(configuration, token, position-1)
+		if err != nil {
+			return "", err
+		}
+		if len(successors) > 0 && !pdaProcessor.isNondeterministic() {
+			nextConfigurations = successors[:1]
+			break
+		}
+		nextConfigurations = append(nextConfigurations, successors...)
+	}
+
+	var transitionTaken string
+	if len(nextConfigurations) > 0 {
+		configurations, err := pdaProcessor.epsilonClosure(nextConfigurations, position-1)
+		if err != nil {
+			return "", err
+		}
+		pdaProcessor.Configurations = configurations
+		pdaProcessor.syncCurrentConfiguration()
+		pdaProcessor.LastConsumedPosition = position
+		transitionTaken = pdaProcessor.CurrentState
+	}
+	return transitionTaken, nil
+}
+
+/**
+return the configurations reachable from configuration by a single move on token at position, in the order of the transitions.
+An empty token only matches epsilon transitions. Every move is counted against the step budget and the stack depth limit.
+*/
+func (pdaProcessor *PDAProcessor) successors(configuration Configuration, token string, position int) ([]Configuration, error) {
+	matching := pdaProcessor.matchingTransitions(configuration, token)
+	successors := make([]Configuration, 0, len(matching))
+	tracing := len(matching) > 0 && pdaProcessor.tracing()
+	for _, index := range matching {
+		transition := pdaProcessor.Transitions[index]
+		if transition.PopAny && configuration.Stack.Len() == 0 {
+			// there is no top to pop
+			continue
+		}
+		if err := pdaProcessor.countStep(position); err != nil {
+			return successors, err
+		}
+		stack := pushOrPopIfRequired(pdaProcessor, configuration.Stack, transition)
+		if err := pdaProcessor.checkStackDepth(stack, position); err != nil {
+			return successors, err
+		}
+		pdaProcessor.PdaClock++
+		trace := configuration.trace.append(index, position, pdaProcessor.PdaClock, configuration.Stack.Top())
+		if tracing {
+			pdaProcessor.Logger().Log(context.Background(), PDA_LOG_LEVEL_TRACE, "transition", "transition", index, "position", position,
+				"token", token, "from_state", configuration.State, "to_state", transition.NextState, "stack_top", configuration.Stack.Top(), "stack", stack)
+		}
+		successors = append(successors, Configuration{State: transition.NextState, Stack: stack, trace: trace})
+	}
+	return successors, nil
+}
+
+/**
+return the indexes of the transitions applicable to configuration on token, in spec order.
+*/
+func (pdaProcessor *PDAProcessor) matchingTransitions(configuration Configuration, token string) []int {
+	if pdaProcessor.transitionIndex == nil {
+		pdaProcessor.compileTransitions()
+	}
+
+	// transitions testing the current stack top and transitions not inspecting the stack, merged back into spec order
+	matching := pdaProcessor.transitionIndex[transitionKey{configuration.State, token, configuration.Stack.Top()}]
+	if configuration.Stack.Len() > 0 {
+		matching = mergeTransitionIndexes(matching, pdaProcessor.transitionIndex[transitionKey{configuration.State, token, ""}])
+	}
+	return matching
+}
+
+/**
+index the transitions by (state, input, stack top) so that put only looks at the transitions which can apply.
+*/
+func (pdaProcessor *PDAProcessor) compileTransitions() {
+	pdaProcessor.transitionIndex = make(map[transitionKey][]int)
+	for index, transition := range pdaProcessor.Transitions {
+		key := transitionKey{transition.State, transition.Input, transition.Pop}
+		pdaProcessor.transitionIndex[key] = append(pdaProcessor.transitionIndex[key], index)
+	}
+}
+
+func mergeTransitionIndexes(first []int, second []int) []int {
+	if len(first) == 0 {
+		return second
+	} else if len(second) == 0 {
+		return first
+	}
+	merged := make([]int, 0, len(first)+len(second))
+	for len(first) > 0 && len(second) > 0 {
+		if first[0] < second[0] {
+			merged, first = append(merged, first[0]), first[1:]
+		} else {
+			merged, second = append(merged, second[0]), second[1:]
+		}
+	}
+	return append(append(merged, first...), second...)
+}
+
+/**
+extend configurations with every configuration reachable from them through epsilon transitions.
+A deterministic PDA follows only the first applicable epsilon transition of each configuration.
+Configurations already in the closure are not expanded again, so epsilon cycles terminate. Epsilon moves growing
+the stack forever are stopped by the step budget, the closure computed so far is returned with the LimitError.
+*/
+func (pdaProcessor *PDAProcessor) epsilonClosure(configurations []Configuration, position int) ([]Configuration, error) {
+	// a single configuration without epsilon moves is its own closure
+	if len(configurations) == 1 && len(pdaProcessor.matchingTransitions(configurations[0], "")) == 0 {
+		return configurations, nil
+	}
+
+	visited := make(map[configurationKey][]*PDAStack)
+	var closure []Configuration
+	for _, configuration := range configurations {
+		if !visitConfiguration(visited, configuration) {
+			closure = append(closure, configuration)
+		}
+	}
+
+	for i := 0; i < len(closure); i++ {
+		successors, err := pdaProcessor.successors(closure[i], "", position)
+		if err != nil {
+			return closure, err
+		}
+		if len(successors) > 0 && !pdaProcessor.isNondeterministic() {
+			successors = successors[:1]
+		}
+		for _, successor := range successors {
+			if visitConfiguration(visited, successor) {
+				if pdaProcessor.tracing() {
+					pdaProcessor.Logger().Log(context.Background(), PDA_LOG_LEVEL_TRACE, "epsilon cycle", "state", successor.State, "stack", successor.Stack)
+				}
+			} else {
+				closure = append(closure, successor)
+			}
+		}
+	}
+	return closure, nil
+}
+
+/**
+EOS announces that position is the position of the last token, the PDA reaches the end of its input once every token
+up to position was consumed.
+*/
+func (pdaProcessor *PDAProcessor) EOS(position int) error {
+	if pdaProcessor.PDAFailedInLastEvaluation {
+		return errors.New("PDA Evaluation failed while consuming last valid token, Please reset PDA before using it")
+	}
+
+	pdaProcessor.Logger().Debug("EOS presented", "position", position)
+	printLog(pdaProcessor)
+	if position < pdaProcessor.LastConsumedPosition-1 {
+		return errors.New("PDA already consumed all the tokens up to position this position")
+	}
+	// set token present
+	pdaProcessor.EOSPresentedAtPosition = position
+
+	if position == pdaProcessor.LastConsumedPosition-1 {
+		// reached EOS
+		_, err := reachedEOS(pdaProcessor)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+QueuedTokens returns the tokens waiting for earlier positions, in position order.
+*/
+func (pdaProcessor *PDAProcessor) QueuedTokens() []string {
+	queue := orderedTokens(pdaProcessor.PendingTokenQueue)
+	pdaProcessor.Logger().Debug("queued tokens", "queue", queue)
+
+	return queue
+}
+
+/**
+return the queued tokens ordered by their position.
+*/
+func orderedTokens(queue map[int]string) []string {
+	positions := make([]int, 0, len(queue))
+	for position := range queue {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+
+	var a []string
+	for _, position := range positions {
+		a = append(a, queue[position])
+	}
+
+	return a
+}
+
+func (pdaProcessor *PDAProcessor) maxPendingTokens() int {
+	if pdaProcessor.MaxPendingTokens > 0 {
+		return pdaProcessor.MaxPendingTokens
+	}
+	return PDA_PENDING_QUEUE_LENGTH
+}
+
+func validateInput(pdaProcessor *PDAProcessor, token string) error {
+	// validate if all token characters are valid input alphabets
+	if len(token) > 0 {
+		found := findInArray(pdaProcessor.InputAlphabet, token)
+		pdaProcessor.PdaClock++
+
+		if !found {
+			return fmt.Errorf("Input token contains unsupported character/string %q", token)
+		}
+	}
+	return nil
+}
+
+func pushToStack(pdaProcessor *PDAProcessor, stack *PDAStack, token string) *PDAStack {
+	pdaProcessor.PdaClock++
+	return stack.push(token)
+}
+
+func popFromStack(pdaProcessor *PDAProcessor, stack *PDAStack) *PDAStack {
+	if stack.Len() >= 1 {
+		pdaProcessor.PdaClock++
+	}
+	return stack.pop()
+}
+
+/**
+replace the stack top matched by transition with the symbols it pushes, the first symbol of Push ending up on top.
+*/
+func pushOrPopIfRequired(pdaProcessor *PDAProcessor, stack *PDAStack, transition Transition) *PDAStack {
+	if transition.popsStack() {
+		stack = popFromStack(pdaProcessor, stack)
+	}
+	for i := len(transition.Push) - 1; i >= 0; i-- {
+		stack = pushToStack(pdaProcessor, stack, transition.Push[i])
+	}
+	return stack
+}
+
+/**
+report the first accepting live configuration, or the first live configuration if none accepts, through CurrentState and Stack.
+*/
+func (pdaProcessor *PDAProcessor) syncCurrentConfiguration() {
+	current := pdaProcessor.Configurations[0]
+	for _, configuration := range pdaProcessor.Configurations {
+		if isAcceptingConfiguration(pdaProcessor, configuration) {
+			current = configuration
+			break
+		}
+	}
+	pdaProcessor.CurrentState = current.State
+	pdaProcessor.Stack = current.Stack
+	pdaProcessor.trace = current.trace
+	pdaProcessor.CurrentStackTop = current.Stack.Top()
+}
+
+func (pdaProcessor *PDAProcessor) isNondeterministic() bool {
+	return pdaProcessor.Mode == PDA_MODE_NONDETERMINISTIC
+}
+
+func isAcceptingConfiguration(pdaProcessor *PDAProcessor, configuration Configuration) bool {
+	if pdaProcessor.Acceptance != PDA_ACCEPTANCE_EMPTY_STACK {
+		// check if state exists in accepting states array
+		if !findInArray(pdaProcessor.AcceptingStates, configuration.State) {
+			return false
+		}
+	}
+	if pdaProcessor.Acceptance != PDA_ACCEPTANCE_FINAL_STATE {
+		// stack is empty when it holds nothing but end of stream marker
+		for stack := configuration.Stack; stack != nil; stack = stack.pop() {
+			if stack.Top() != pdaProcessor.Eos {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type configurationKey struct {
+	state string
+	depth int
+	hash  uint64
+}
+
+/**
+record configuration as visited, returns true if an equal configuration was visited before.
+*/
+func visitConfiguration(visited map[configurationKey][]*PDAStack, configuration Configuration) bool {
+	key := configurationKey{configuration.State, configuration.Stack.Len(), configuration.Stack.contentHash()}
+	for _, stack := range visited[key] {
+		if stack.equals(configuration.Stack) {
+			return true
+		}
+	}
+	visited[key] = append(visited[key], configuration.Stack)
+	return false
+}
+
+func findInArray(arr []string, value string) bool {
+	for _, a := range arr {
+		if strings.Compare(a, value) == 0 {
+			return true
+		}
+	}
+	return false
+}
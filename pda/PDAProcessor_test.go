@@ -0,0 +1,323 @@
+package pda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// keep per-reset info logs out of the benchmark output
+	pdaLogLevel.Set(slog.LevelWarn)
+	os.Exit(m.Run())
+}
+
+/**
+specification of a PDA with 2*symbols transitions: q0 pushes the token it reads and moves to q1, q1 pops the same token
+and moves back to q0. The stack stays at most one symbol deep, so the cost of a step is the lookup of its transition.
+*/
+func matchingPairsSpecification(tb testing.TB, symbols int) []byte {
+	alphabet := make([]string, symbols)
+	transitions := make([]Transition, 0, 2*symbols)
+	for i := range alphabet {
+		alphabet[i] = "t" + strconv.Itoa(i)
+		transitions = append(transitions, Transition{State: "q0", Input: alphabet[i], NextState: "q1", Push: []string{alphabet[i]}})
+	}
+	for _, symbol := range alphabet {
+		transitions = append(transitions, Transition{State: "q1", Input: symbol, Pop: symbol, NextState: "q0"})
+	}
+	specification, err := json.Marshal(map[string]interface{}{
+		"name":             "matching pairs",
+		"states":           []string{"q0", "q1"},
+		"input_alphabet":   alphabet,
+		"stack_alphabet":   alphabet,
+		"accepting_states": []string{"q0"},
+		"start_state":      "q0",
+		"transitions":      transitions,
+		"eos":              "$",
+		"mode":             PDA_MODE_DETERMINISTIC,
+		"acceptance":       PDA_ACCEPTANCE_FINAL_STATE,
+		"max_total_steps":  1 << 62,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return specification
+}
+
+func loadSpecification(tb testing.TB, specification []byte) *PDAProcessor {
+	pdaProcessor := &PDAProcessor{}
+	if err := pdaProcessor.Load(specification); err != nil {
+		tb.Fatal(err)
+	}
+	return pdaProcessor
+}
+
+func TestMatchingPairsSpecification(t *testing.T) {
+	pdaProcessor := loadSpecification(t, matchingPairsSpecification(t, 100))
+	if _, _, err := pdaProcessor.EvaluateInput("t7 t7 t99 t99 t0 t0"); err != nil {
+		t.Fatal(err)
+	}
+	if !pdaProcessor.IsAccepted() {
+		t.Fatal("matching pairs were rejected")
+	}
+
+	pdaProcessor = loadSpecification(t, matchingPairsSpecification(t, 100))
+	if _, _, err := pdaProcessor.EvaluateInput("t7 t8"); err != nil {
+		t.Fatal(err)
+	}
+	if pdaProcessor.IsAccepted() {
+		t.Fatal("mismatched pair was accepted")
+	}
+}
+
+/**
+specification over the input alphabet {a, b} and the stack alphabet {a, b, X} with given states, transitions and mode,
+accepting in state "f" with a stack holding at most the eos marker.
+*/
+func specificationWith(states string, transitions string, mode string) []byte {
+	return []byte(fmt.Sprintf(`{
+		"name": "test",
+		"states": [%s],
+		"input_alphabet": ["a", "b"],
+		"stack_alphabet": ["a", "b", "X"],
+		"accepting_states": ["f"],
+		"start_state": "q0",
+		"transitions": [%s],
+		"eos": "$",
+		"mode": %q,
+		"max_stack_depth": 50
+	}`, states, transitions, mode))
+}
+
+// even length palindromes, the midpoint is guessed by an epsilon move from q1 to q2
+const evenPalindromeTransitions = `
+	["q0", null, null, "q1", "$"],
+	["q1", "a", null, "q1", "a"],
+	["q1", "b", null, "q1", "b"],
+	{"state": "q1", "next_state": "q2"},
+	["q2", "a", "a", "q2", null],
+	["q2", "b", "b", "q2", null],
+	["q2", null, "$", "f", null]`
+
+func TestNondeterministicAcceptance(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		accepted bool
+	}{
+		{"a a", true},
+		{"a b b a", true},
+		{"b a a b b a a b", true},
+		{"a b", false},
+		{"a b a", false},
+		{"a b a b", false},
+		{"a a a", false},
+	} {
+		pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f"`, evenPalindromeTransitions, PDA_MODE_NONDETERMINISTIC))
+		if _, _, err := pdaProcessor.EvaluateInput(test.input); err != nil {
+			t.Fatalf("%q: %v", test.input, err)
+		}
+		if pdaProcessor.IsAccepted() != test.accepted {
+			t.Errorf("%q: got accepted %t, want %t", test.input, pdaProcessor.IsAccepted(), test.accepted)
+		}
+	}
+
+	// a deterministic PDA only moves the first configuration that can read a token, which is the one still pushing, so
+	// the guessed midpoint is never followed
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f"`, evenPalindromeTransitions, PDA_MODE_DETERMINISTIC))
+	if _, _, err := pdaProcessor.EvaluateInput("a a"); err != nil {
+		t.Fatal(err)
+	}
+	if pdaProcessor.IsAccepted() {
+		t.Error("deterministic mode accepted a a by guessing the midpoint")
+	}
+}
+
+func TestDeterministicModeTakesFirstMatch(t *testing.T) {
+	toAccepting := `{"state": "q0", "input": "a", "next_state": "f"}`
+	toRejecting := `{"state": "q0", "input": "a", "next_state": "r"}`
+	for _, test := range []struct {
+		mode        string
+		transitions string
+		accepted    bool
+		state       string
+	}{
+		{PDA_MODE_DETERMINISTIC, toAccepting + "," + toRejecting, true, "f"},
+		{PDA_MODE_DETERMINISTIC, toRejecting + "," + toAccepting, false, "r"},
+		{PDA_MODE_NONDETERMINISTIC, toAccepting + "," + toRejecting, true, "f"},
+		{PDA_MODE_NONDETERMINISTIC, toRejecting + "," + toAccepting, true, "f"},
+	} {
+		pdaProcessor := loadSpecification(t, specificationWith(`"q0", "f", "r"`, test.transitions, test.mode))
+		if _, _, err := pdaProcessor.EvaluateInput("a"); err != nil {
+			t.Fatal(err)
+		}
+		if pdaProcessor.IsAccepted() != test.accepted || pdaProcessor.State() != test.state {
+			t.Errorf("%s [%s]: got state %q accepted %t, want %q %t", test.mode, test.transitions, pdaProcessor.State(), pdaProcessor.IsAccepted(), test.state, test.accepted)
+		}
+	}
+}
+
+func TestEpsilonCyclesEnd(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		transitions string
+	}{
+		{"self loop", `{"state": "q1", "next_state": "q1"}`},
+		{"two state cycle", `{"state": "q1", "next_state": "q2"}, {"state": "q2", "next_state": "q1"}`},
+		{"push and pop", `["q1", null, null, "q2", "X"], ["q2", null, "X", "q1", null]`},
+	} {
+		transitions := `{"state": "q0", "input": "a", "next_state": "q1"}, ` + test.transitions + `, {"state": "q1", "input": "b", "next_state": "f"}`
+		for _, mode := range []string{PDA_MODE_DETERMINISTIC, PDA_MODE_NONDETERMINISTIC} {
+			pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "q2", "f"`, transitions, mode))
+			if _, _, err := pdaProcessor.EvaluateInput("a b"); err != nil {
+				t.Fatalf("%s in %s mode: %v", test.name, mode, err)
+			}
+			if !pdaProcessor.IsAccepted() {
+				t.Errorf("%s in %s mode: a b was rejected", test.name, mode)
+			}
+		}
+	}
+}
+
+func TestEpsilonLoops(t *testing.T) {
+	for _, mode := range []string{PDA_MODE_DETERMINISTIC, PDA_MODE_NONDETERMINISTIC} {
+		// the closure revisits the same (state, stack) configuration and stops there
+		pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "f"`, `["q0", "a", null, "q1", "X"], {"state": "q1", "next_state": "q1"}`, mode))
+		if _, _, err := pdaProcessor.EvaluateInput("a"); err != nil {
+			t.Fatalf("%s mode: loop without push failed with %v", mode, err)
+		}
+		if pdaProcessor.State() != "q1" || pdaProcessor.Steps > 10 {
+			t.Fatalf("%s mode: loop without push ended in %q after %d steps", mode, pdaProcessor.State(), pdaProcessor.Steps)
+		}
+
+		// every round adds a symbol, so no configuration repeats and the stack depth limit ends the loop
+		pdaProcessor = loadSpecification(t, specificationWith(`"q0", "q1", "f"`, `{"state": "q0", "input": "a", "next_state": "q1"}, ["q1", null, null, "q1", "X"]`, mode))
+		_, _, err := pdaProcessor.EvaluateInput("a")
+		var limitError *LimitError
+		if !errors.As(err, &limitError) || limitError.Limit != "max_stack_depth" || limitError.Value != 50 {
+			t.Fatalf("%s mode: pushing loop failed with %v, want a max_stack_depth LimitError", mode, err)
+		}
+		if !pdaProcessor.PDAFailedInLastEvaluation {
+			t.Fatalf("%s mode: pushing loop did not fail the evaluation", mode)
+		}
+	}
+}
+
+func TestTransducerOutput(t *testing.T) {
+	specification := `{
+		"name": "sum to postfix",
+		"states": ["q0", "q1", "q2", "q3"],
+		"input_alphabet": ["x", "+"],
+		"stack_alphabet": ["$", "+"],
+		"accepting_states": ["q3"],
+		"start_state": "q0",
+		"transitions": [
+			["q0", null, null, "q1", "$"],
+			{"state": "q1", "input": "x", "pop": "$", "next_state": "q2", "push": ["$"], "output": "x"},
+			{"state": "q1", "input": "x", "pop": "+", "next_state": "q2", "push": [], "output": "x +"},
+			["q2", "+", null, "q1", "+"],
+			["q2", null, "$", "q3", null]
+		],
+		"eos": "$"%s
+	}`
+	for _, test := range []struct {
+		separator string
+		output    string
+	}{
+		{``, "xx +x +"},
+		{`, "output_separator": " "`, "x x + x +"},
+	} {
+		pdaProcessor := loadSpecification(t, []byte(fmt.Sprintf(specification, test.separator)))
+		_, output, err := pdaProcessor.EvaluateInput("x + x + x")
+		if err != nil || !pdaProcessor.IsAccepted() {
+			t.Fatal("sum was rejected", err)
+		}
+		// the epsilon moves write nothing, so they add no separator
+		if strings.Join(output, "|") != "x|x +|x +" {
+			t.Fatalf("got output symbols %q", output)
+		}
+		if pdaProcessor.Output() != test.output {
+			t.Fatalf("got output %q, want %q", pdaProcessor.Output(), test.output)
+		}
+	}
+}
+
+/**
+stream tokens one Step at a time through PDAs with growing transition tables. Transitions are indexed by (state, input,
+stack top), so the time per token stays flat however many transitions there are.
+*/
+func BenchmarkStepIndexed(b *testing.B) {
+	for _, symbols := range []int{10, 1000, 10000} {
+		b.Run(strconv.Itoa(2*symbols)+"-transitions", func(b *testing.B) {
+			pdaProcessor := loadSpecification(b, matchingPairsSpecification(b, symbols))
+			tokens := make([]string, 2*symbols)
+			for i := 0; i < symbols; i++ {
+				tokens[2*i] = "t" + strconv.Itoa(i)
+				tokens[2*i+1] = tokens[2*i]
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			position := 0
+			for i := 0; i < b.N; i++ {
+				// the trace grows with every token, start over now and then to keep memory bounded on long runs
+				if position == 1<<20 {
+					b.StopTimer()
+					pdaProcessor.Reset()
+					position = 0
+					b.StartTimer()
+				}
+				position++
+				if state, err := pdaProcessor.Step(position, tokens[(position-1)%len(tokens)]); err != nil || state == "" {
+					b.Fatalf("token %d rejected: %v", position, err)
+				}
+			}
+		})
+	}
+}
+
+/**
+evaluate a stream of millions of tokens, 0^n1^n with n = 1000000, reporting the throughput in tokens per second.
+*/
+func BenchmarkEvaluateInput(b *testing.B) {
+	const n = 1000000
+	specification := []byte(`{
+		"name": "0^n1^n",
+		"states": ["q1", "q2", "q3", "q4"],
+		"input_alphabet": ["0", "1"],
+		"stack_alphabet": ["0", "1"],
+		"accepting_states": ["q1", "q4"],
+		"start_state": "q1",
+		"transitions": [
+			["q1", null, null, "q2", "$"],
+			["q2", "0", null, "q2", "0"],
+			["q2", "1", "0", "q3", null],
+			["q3", "1", "0", "q3", null],
+			["q3", null, "$", "q4", null]
+		],
+		"eos": "$",
+		"max_stack_depth": 2000000,
+		"max_total_steps": 10000000
+	}`)
+	input := strings.Repeat("0 ", n) + strings.TrimSpace(strings.Repeat("1 ", n))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		pdaProcessor := loadSpecification(b, specification)
+		b.StartTimer()
+		if _, _, err := pdaProcessor.EvaluateInput(input); err != nil {
+			b.Fatal(err)
+		}
+		if !pdaProcessor.IsAccepted() {
+			b.Fatal("0^n1^n was rejected")
+		}
+	}
+	b.ReportMetric(float64(2*n)*float64(b.N)/b.Elapsed().Seconds(), "tokens/s")
+}
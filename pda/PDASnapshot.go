@@ -0,0 +1,135 @@
+package pda
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+/**
+SessionSnapshot is a versioned, self contained copy of a live PDA session: the specification it runs and its runtime state.
+*/
+type SessionSnapshot struct {
+	Version                   int                     `json:"version"`
+	Specification             PDAProcessor            `json:"specification"`
+	Configurations            []ConfigurationSnapshot `json:"configurations"`
+	PendingTokenQueue         map[int]string          `json:"pending_token_queue"`
+	LastConsumedPosition      int                     `json:"last_consumed_position"`
+	EOSPresentedAtPosition    int                     `json:"eos_presented_at_position"`
+	PdaClock                  int                     `json:"pda_clock"`
+	Steps                     int                     `json:"steps"`
+	PDAFailedInLastEvaluation bool                    `json:"pda_failed_in_last_evaluation"`
+}
+
+/**
+ConfigurationSnapshot is a live configuration with its stack listed bottom to top and the moves which led to it.
+*/
+type ConfigurationSnapshot struct {
+	State string      `json:"state"`
+	Stack []string    `json:"stack"`
+	Trace []TraceStep `json:"trace"`
+}
+
+/**
+ExportSnapshot captures the runtime state of the PDA so that it can be restored later, possibly on another server.
+*/
+func (pdaProcessor *PDAProcessor) ExportSnapshot() SessionSnapshot {
+	snapshot := SessionSnapshot{
+		Version:                   PDA_SNAPSHOT_VERSION,
+		Specification:             *pdaProcessor,
+		PendingTokenQueue:         make(map[int]string),
+		LastConsumedPosition:      pdaProcessor.LastConsumedPosition,
+		EOSPresentedAtPosition:    pdaProcessor.EOSPresentedAtPosition,
+		PdaClock:                  pdaProcessor.PdaClock,
+		Steps:                     pdaProcessor.Steps,
+		PDAFailedInLastEvaluation: pdaProcessor.PDAFailedInLastEvaluation,
+	}
+	for _, configuration := range pdaProcessor.Configurations {
+		snapshot.Configurations = append(snapshot.Configurations, ConfigurationSnapshot{
+			State: configuration.State,
+			Stack: configuration.Stack.Symbols(),
+			Trace: configuration.trace.steps(pdaProcessor.Transitions),
+		})
+	}
+	for position, token := range pdaProcessor.PendingTokenQueue {
+		snapshot.PendingTokenQueue[position] = token
+	}
+	return snapshot
+}
+
+/**
+ImportSnapshot replaces the runtime state of the PDA with the one captured in snapshot. The PDA has to be opened with the same specification,
+and the snapshot has to stay within its limits: no stack deeper than max_stack_depth and no more queued tokens than
+max_pending_tokens, every one of them in the input alphabet.
+*/
+func (pdaProcessor *PDAProcessor) ImportSnapshot(snapshot SessionSnapshot) error {
+	if snapshot.Version != PDA_SNAPSHOT_VERSION {
+		return fmt.Errorf("unsupported snapshot version %d, expected %d", snapshot.Version, PDA_SNAPSHOT_VERSION)
+	}
+	if !sameSpecification(*pdaProcessor, snapshot.Specification) {
+		return fmt.Errorf("snapshot was taken from a different specification of PDA %d", pdaProcessor.ID)
+	}
+	if len(snapshot.Configurations) == 0 {
+		return errors.New("snapshot has no live configuration")
+	}
+
+	var configurations []Configuration
+	for i, configurationSnapshot := range snapshot.Configurations {
+		if !findInArray(pdaProcessor.States, configurationSnapshot.State) {
+			return fmt.Errorf("configurations[%d]: state %q is not one of the states", i, configurationSnapshot.State)
+		}
+		for _, symbol := range configurationSnapshot.Stack {
+			if symbol != pdaProcessor.Eos && !findInArray(pdaProcessor.StackAlphabet, symbol) {
+				return fmt.Errorf("configurations[%d]: stack symbol %q is not in the stack alphabet", i, symbol)
+			}
+		}
+		if len(configurationSnapshot.Stack) > pdaProcessor.maxStackDepth() {
+			return fmt.Errorf("configurations[%d]: stack of %d symbols exceeds the max_stack_depth of %d", i, len(configurationSnapshot.Stack), pdaProcessor.maxStackDepth())
+		}
+
+		var trace *traceNode
+		for j, step := range configurationSnapshot.Trace {
+			if step.Transition < 0 || step.Transition >= len(pdaProcessor.Transitions) {
+				return fmt.Errorf("configurations[%d].trace[%d]: transition %d does not exist", i, j, step.Transition)
+			}
+			trace = trace.append(step.Transition, step.Position, step.Clock, step.StackTop)
+		}
+		configurations = append(configurations, Configuration{State: configurationSnapshot.State, Stack: newPDAStack(configurationSnapshot.Stack), trace: trace})
+	}
+
+	if len(snapshot.PendingTokenQueue) > pdaProcessor.maxPendingTokens() {
+		return fmt.Errorf("snapshot queues %d tokens, at most %d tokens can wait for earlier positions", len(snapshot.PendingTokenQueue), pdaProcessor.maxPendingTokens())
+	}
+	positions := make([]int, 0, len(snapshot.PendingTokenQueue))
+	for position := range snapshot.PendingTokenQueue {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+	for _, position := range positions {
+		if token := snapshot.PendingTokenQueue[position]; !findInArray(pdaProcessor.InputAlphabet, token) {
+			return fmt.Errorf("pending_token_queue[%d]: token %q is not in the input alphabet", position, token)
+		}
+	}
+
+	pdaProcessor.Configurations = configurations
+	pdaProcessor.syncCurrentConfiguration()
+	pdaProcessor.PendingTokenQueue = make(map[int]string)
+	for position, token := range snapshot.PendingTokenQueue {
+		pdaProcessor.PendingTokenQueue[position] = token
+	}
+	pdaProcessor.LastConsumedPosition = snapshot.LastConsumedPosition
+	pdaProcessor.EOSPresentedAtPosition = snapshot.EOSPresentedAtPosition
+	pdaProcessor.PdaClock = snapshot.PdaClock
+	pdaProcessor.Steps = snapshot.Steps
+	pdaProcessor.PDAFailedInLastEvaluation = snapshot.PDAFailedInLastEvaluation
+	pdaProcessor.checkpoints = nil
+	return nil
+}
+
+func sameSpecification(first PDAProcessor, second PDAProcessor) bool {
+	firstJson, err1 := json.Marshal(first)
+	secondJson, err2 := json.Marshal(second)
+	return err1 == nil && err2 == nil && bytes.Equal(firstJson, secondJson)
+}
@@ -0,0 +1,76 @@
+package pda
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+/**
+PDA counting a^n b^n which queues at most two tokens, opened fresh for every import.
+*/
+func snapshotTestPDA(t *testing.T) *PDAProcessor {
+	pdaProcessor := loadSpecification(t, specificationWith(`"q0", "q1", "f"`, countingTransitions, PDA_MODE_DETERMINISTIC))
+	pdaProcessor.MaxPendingTokens = 2
+	return pdaProcessor
+}
+
+func TestImportSnapshot(t *testing.T) {
+	session := snapshotTestPDA(t)
+	// b waits in the queue for position 2
+	for _, token := range []struct {
+		position int
+		symbol   string
+	}{{0, "a"}, {1, "a"}, {3, "b"}} {
+		if _, err := session.Put(token.position, token.symbol); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exported, err := json.Marshal(session.ExportSnapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name   string
+		change func(snapshot *SessionSnapshot)
+		err    string
+	}{
+		{"unchanged", func(snapshot *SessionSnapshot) {}, ""},
+		{"other version", func(snapshot *SessionSnapshot) { snapshot.Version++ }, "unsupported snapshot version"},
+		{"other specification", func(snapshot *SessionSnapshot) { snapshot.Specification.Name = "other" }, "different specification"},
+		{"undeclared state", func(snapshot *SessionSnapshot) { snapshot.Configurations[0].State = "q9" }, "is not one of the states"},
+		{"stack too deep", func(snapshot *SessionSnapshot) {
+			snapshot.Configurations[0].Stack = append([]string{"$"}, strings.Fields(strings.Repeat("a ", 50))...)
+		}, "exceeds the max_stack_depth of 50"},
+		{"queue too long", func(snapshot *SessionSnapshot) {
+			snapshot.PendingTokenQueue = map[int]string{3: "b", 4: "b", 5: "b"}
+		}, "at most 2 tokens"},
+		{"queued token outside the alphabet", func(snapshot *SessionSnapshot) { snapshot.PendingTokenQueue[3] = "c" }, `token "c" is not in the input alphabet`},
+	} {
+		var snapshot SessionSnapshot
+		if err := json.Unmarshal(exported, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+		test.change(&snapshot)
+
+		pdaProcessor := snapshotTestPDA(t)
+		err := pdaProcessor.ImportSnapshot(snapshot)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: got %v, want an error containing %q", test.name, err, test.err)
+			}
+			if pdaProcessor.State() != "q0" || len(pdaProcessor.QueuedTokens()) != 0 {
+				t.Errorf("%s: rejected snapshot changed the PDA", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(pdaProcessor.Peek(3), session.Peek(3)) || !reflect.DeepEqual(pdaProcessor.QueuedTokens(), []string{"b"}) || pdaProcessor.Steps != session.Steps {
+			t.Errorf("%s: got stack %v, queue %v and %d steps, want %v, [b] and %d", test.name, pdaProcessor.Peek(3), pdaProcessor.QueuedTokens(), pdaProcessor.Steps, session.Peek(3), session.Steps)
+		}
+	}
+}
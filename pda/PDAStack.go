@@ -0,0 +1,106 @@
+package pda
+
+import (
+	"fmt"
+)
+
+/**
+PDAStack is an immutable PDA stack, a nil *PDAStack is the empty stack. push and pop return a new stack and never change the receiver,
+so configurations branching from the same history share everything below the point where they diverge.
+*/
+type PDAStack struct {
+	symbol string
+	below  *PDAStack
+	depth  int
+	hash   uint64
+}
+
+func (stack *PDAStack) push(symbol string) *PDAStack {
+	// FNV-1a over the symbols, seeded with the hash of the stack below
+	hash := stack.contentHash() ^ 14695981039346656037
+	for i := 0; i < len(symbol); i++ {
+		hash = (hash ^ uint64(symbol[i])) * 1099511628211
+	}
+	return &PDAStack{symbol: symbol, below: stack, depth: stack.Len() + 1, hash: (hash ^ 0xff) * 1099511628211}
+}
+
+func (stack *PDAStack) pop() *PDAStack {
+	if stack == nil {
+		return nil
+	}
+	return stack.below
+}
+
+/**
+Top returns the top symbol, or "" when the stack is empty.
+*/
+func (stack *PDAStack) Top() string {
+	if stack == nil {
+		return ""
+	}
+	return stack.symbol
+}
+
+/**
+Len returns the number of symbols on the stack.
+*/
+func (stack *PDAStack) Len() int {
+	if stack == nil {
+		return 0
+	}
+	return stack.depth
+}
+
+func (stack *PDAStack) contentHash() uint64 {
+	if stack == nil {
+		return 0
+	}
+	return stack.hash
+}
+
+/**
+Symbols returns the stack contents from bottom to top, an empty stack returns an empty slice.
+*/
+func (stack *PDAStack) Symbols() []string {
+	symbols := make([]string, stack.Len())
+	for node, i := stack, stack.Len()-1; node != nil; node, i = node.below, i-1 {
+		symbols[i] = node.symbol
+	}
+	return symbols
+}
+
+/**
+Peek returns up to k symbols from the top of the stack, bottom to top.
+*/
+func (stack *PDAStack) Peek(k int) []string {
+	if k > stack.Len() {
+		k = stack.Len()
+	}
+	symbols := make([]string, k)
+	for node, i := stack, k-1; i >= 0; node, i = node.below, i-1 {
+		symbols[i] = node.symbol
+	}
+	return symbols
+}
+
+func (stack *PDAStack) equals(other *PDAStack) bool {
+	for stack != other {
+		if stack.Len() != other.Len() || stack.contentHash() != other.contentHash() || stack.symbol != other.symbol {
+			return false
+		}
+		stack, other = stack.below, other.below
+	}
+	return true
+}
+
+func newPDAStack(symbols []string) *PDAStack {
+	var stack *PDAStack
+	for _, symbol := range symbols {
+		stack = stack.push(symbol)
+	}
+	return stack
+}
+
+func (stack *PDAStack) String() string {
+	return fmt.Sprintf("%+v", stack.Symbols())
+}
@@ -0,0 +1,259 @@
+package pda
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+transition popping exactly one stack symbol, the form the triple construction works on. Origin is the index of the
+transition of the specification it stems from, -1 for the moves added to accept.
+*/
+type normalizedTransition struct {
+	state     string
+	input     string
+	pop       string
+	nextState string
+	push      []string
+	origin    int
+}
+
+/**
+ToGrammar converts the PDA into an equivalent context-free grammar with the triple construction. The nonterminal [q,X,p] derives
+the input read while the PDA goes from state q to state p and removes X from the top of the stack, the start symbol S
+derives [start,bottom,final] of the normalized PDA. Nonterminals which derive no terminal string or can not be reached
+from S are pruned, S has no productions when the PDA accepts nothing. Every applicable transition is followed, as in
+nondeterministic mode, so the grammar of a deterministic PDA with conflicting transitions may derive more.
+*/
+func (pdaProcessor *PDAProcessor) ToGrammar() (Grammar, error) {
+	return pdaProcessor.toGrammar(PDA_GRAMMAR_MAX_PRODUCTIONS)
+}
+
+/**
+convert the PDA into its grammar like ToGrammar, giving up when the triple construction has more than maxProductions
+productions before pruning.
+*/
+func (pdaProcessor *PDAProcessor) toGrammar(maxProductions int) (Grammar, error) {
+	start, productions, _, err := pdaProcessor.tripleProductions(maxProductions)
+	if err != nil {
+		return Grammar{}, err
+	}
+
+	pruned := []Production{}
+	for _, i := range usefulProductions(productions, start, pdaProcessor.InputAlphabet) {
+		pruned = append(pruned, productions[i])
+	}
+	return Grammar{
+		Name:        pdaProcessor.Name,
+		Terminals:   append([]string{}, pdaProcessor.InputAlphabet...),
+		Start:       start,
+		Productions: pruned,
+		Tokenizer:   pdaProcessor.Tokenizer,
+	}, nil
+}
+
+/**
+return the start symbol and the productions of the triple construction before pruning, along with the index of the
+transition each production stems from, -1 for the productions of the start symbol and of accepting. Fails when there
+would be more than maxProductions productions.
+*/
+func (pdaProcessor *PDAProcessor) tripleProductions(maxProductions int) (string, []Production, []int, error) {
+	stackSymbols := append([]string{}, pdaProcessor.StackAlphabet...)
+	if !findInArray(stackSymbols, pdaProcessor.Eos) {
+		stackSymbols = append(stackSymbols, pdaProcessor.Eos)
+	}
+	// the PDA starts with an empty stack, the normalized one with a bottom marker which is popped only to accept
+	bottom := freshName("Z0", stackSymbols)
+	drain := freshName("q_drain", pdaProcessor.States)
+	final := freshName("q_final", append(append([]string{}, pdaProcessor.States...), drain))
+
+	var transitions []normalizedTransition
+	for index, transition := range pdaProcessor.Transitions {
+		if transition.Pop != "" {
+			transitions = append(transitions, normalizedTransition{transition.State, transition.Input, transition.Pop, transition.NextState, transition.Push, index})
+			continue
+		}
+		if transition.PopAny {
+			// a transition popping any top applies on every symbol but the bottom marker, which the PDA does not have
+			for _, top := range stackSymbols {
+				transitions = append(transitions, normalizedTransition{transition.State, transition.Input, top, transition.NextState, transition.Push, index})
+			}
+			continue
+		}
+		// a transition not inspecting the stack applies on any top, which it leaves below the pushed symbols
+		for _, top := range append(append([]string{}, stackSymbols...), bottom) {
+			push := append(append([]string{}, transition.Push...), top)
+			transitions = append(transitions, normalizedTransition{transition.State, transition.Input, top, transition.NextState, push, index})
+		}
+	}
+
+	// accept by emptying the stack down to the bottom marker, from the states and over the symbols acceptance allows
+	acceptingStates := pdaProcessor.AcceptingStates
+	if pdaProcessor.Acceptance == PDA_ACCEPTANCE_EMPTY_STACK {
+		acceptingStates = pdaProcessor.States
+	}
+	drainedSymbols := []string{pdaProcessor.Eos}
+	if pdaProcessor.Acceptance == PDA_ACCEPTANCE_FINAL_STATE {
+		drainedSymbols = stackSymbols
+	}
+	for _, state := range append(append([]string{}, acceptingStates...), drain) {
+		transitions = append(transitions, normalizedTransition{state, "", bottom, final, nil, -1})
+		for _, symbol := range drainedSymbols {
+			transitions = append(transitions, normalizedTransition{state, "", symbol, drain, nil, -1})
+		}
+	}
+
+	states := append(append([]string{}, pdaProcessor.States...), drain, final)
+	count := 0
+	for _, transition := range transitions {
+		combinations := 1
+		for range transition.push {
+			combinations *= len(states)
+			if combinations > maxProductions {
+				break
+			}
+		}
+		count += combinations
+		if count > maxProductions {
+			return "", nil, nil, fmt.Errorf("grammar of PDA %d would have more than %d productions before pruning", pdaProcessor.ID, maxProductions)
+		}
+	}
+
+	nonterminal := func(state string, symbol string, nextState string) string {
+		return freshName("["+state+","+symbol+","+nextState+"]", pdaProcessor.InputAlphabet)
+	}
+	start := freshName("S", pdaProcessor.InputAlphabet)
+	productions := []Production{{Head: start, Body: []string{nonterminal(pdaProcessor.StartState, bottom, final)}}}
+	origins := []int{-1}
+	for _, transition := range transitions {
+		var terminals []string
+		if transition.input != "" {
+			terminals = []string{transition.input}
+		}
+		if len(transition.push) == 0 {
+			productions = append(productions, Production{Head: nonterminal(transition.state, transition.pop, transition.nextState), Body: append([]string{}, terminals...)})
+			origins = append(origins, transition.origin)
+			continue
+		}
+
+		// [state,pop,s_k] -> input [next_state,push_1,s_1][s_1,push_2,s_2]...[s_k-1,push_k,s_k] for all states s_1..s_k
+		through := make([]int, len(transition.push))
+		for {
+			body := append([]string{}, terminals...)
+			from := transition.nextState
+			for i, symbol := range transition.push {
+				body = append(body, nonterminal(from, symbol, states[through[i]]))
+				from = states[through[i]]
+			}
+			productions = append(productions, Production{Head: nonterminal(transition.state, transition.pop, from), Body: body})
+			origins = append(origins, transition.origin)
+
+			i := len(through) - 1
+			for ; i >= 0 && through[i] == len(states)-1; i-- {
+				through[i] = 0
+			}
+			if i < 0 {
+				break
+			}
+			through[i]++
+		}
+	}
+
+	return start, productions, origins, nil
+}
+
+/**
+return the indexes of the productions left after dropping duplicate productions and the productions using a
+nonterminal that derives no terminal string or is not reachable from start, in their order. Of duplicate productions
+the first one is kept.
+*/
+func usefulProductions(productions []Production, start string, terminals []string) []int {
+	isTerminal := make(map[string]bool)
+	for _, terminal := range terminals {
+		isTerminal[terminal] = true
+	}
+
+	// productive nonterminals, a production becomes productive once none of its body nonterminals is pending
+	seen := make(map[string]bool)
+	var unique []Production
+	var indexes []int
+	pending := make([]int, 0, len(productions))
+	usedBy := make(map[string][]int)
+	for i, production := range productions {
+		key := production.Head + " -> " + strings.Join(production.Body, " ")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		count := 0
+		for _, symbol := range production.Body {
+			if !isTerminal[symbol] {
+				usedBy[symbol] = append(usedBy[symbol], len(unique))
+				count++
+			}
+		}
+		unique = append(unique, production)
+		indexes = append(indexes, i)
+		pending = append(pending, count)
+	}
+	productive := make(map[string]bool)
+	var worklist []string
+	for i, production := range unique {
+		if pending[i] == 0 && !productive[production.Head] {
+			productive[production.Head] = true
+			worklist = append(worklist, production.Head)
+		}
+	}
+	for len(worklist) > 0 {
+		symbol := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		for _, i := range usedBy[symbol] {
+			pending[i]--
+			if pending[i] == 0 && !productive[unique[i].Head] {
+				productive[unique[i].Head] = true
+				worklist = append(worklist, unique[i].Head)
+			}
+		}
+	}
+
+	byHead := make(map[string][]int)
+	for i, production := range unique {
+		if pending[i] == 0 {
+			byHead[production.Head] = append(byHead[production.Head], i)
+		}
+	}
+
+	// reachable nonterminals through productive productions
+	reachable := map[string]bool{start: true}
+	worklist = []string{start}
+	for len(worklist) > 0 {
+		symbol := worklist[len(worklist)-1]
+		worklist = worklist[:len(worklist)-1]
+		for _, i := range byHead[symbol] {
+			for _, next := range unique[i].Body {
+				if !isTerminal[next] && !reachable[next] {
+					reachable[next] = true
+					worklist = append(worklist, next)
+				}
+			}
+		}
+	}
+
+	useful := []int{}
+	for i, production := range unique {
+		if pending[i] == 0 && reachable[production.Head] {
+			useful = append(useful, indexes[i])
+		}
+	}
+	return useful
+}
+
+/**
+return name, with primes appended until it is not one of taken.
+*/
+func freshName(name string, taken []string) string {
+	for findInArray(taken, name) {
+		name += "'"
+	}
+	return name
+}
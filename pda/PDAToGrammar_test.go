@@ -0,0 +1,109 @@
+package pda
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/**
+the inputs of up to maxLength tokens the PDA accepts.
+*/
+func acceptedInputs(t *testing.T, pdaProcessor *PDAProcessor, maxLength int) map[string]bool {
+	accepted := make(map[string]bool)
+	for _, input := range inputsUpTo(pdaProcessor.InputAlphabet, maxLength) {
+		if err := pdaProcessor.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := pdaProcessor.EvaluateInput(input); err != nil {
+			t.Fatalf("%q failed with %v", input, err)
+		}
+		if pdaProcessor.IsAccepted() {
+			accepted[input] = true
+		}
+	}
+	return accepted
+}
+
+/**
+convert the PDA into a grammar and the grammar back into a PDA, which has to accept the same inputs of up to maxLength
+tokens.
+*/
+func assertGrammarRoundTrip(t *testing.T, name string, pdaProcessor *PDAProcessor, maxLength int) {
+	t.Helper()
+	accepted := acceptedInputs(t, pdaProcessor, maxLength)
+	if len(accepted) == 0 {
+		t.Fatalf("%s: accepts no input of up to %d tokens, the round trip would prove nothing", name, maxLength)
+	}
+
+	grammar, err := pdaProcessor.ToGrammar()
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	roundTrip, err := grammar.ToPDA(pdaProcessor.ID)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if err := roundTrip.Init(); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	assertAcceptsExactly(t, name, &roundTrip, accepted, maxLength)
+}
+
+func TestGrammarRoundTripOfShippedSpecifications(t *testing.T) {
+	files, err := filepath.Glob("../PDAFiles/*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no specifications found: %v", err)
+	}
+	for _, file := range files {
+		specification, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertGrammarRoundTrip(t, file, loadSpecification(t, specification), 8)
+	}
+}
+
+func TestGrammarRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		specification []byte
+	}{
+		{"nondeterministic palindromes", specificationWith(`"q0", "q1", "q2", "f"`, evenPalindromeTransitions, PDA_MODE_NONDETERMINISTIC)},
+		{"final state acceptance", []byte(`{
+			"name": "a^n b^m, m <= n",
+			"states": ["q0", "q1"],
+			"input_alphabet": ["a", "b"],
+			"stack_alphabet": ["a"],
+			"accepting_states": ["q0", "q1"],
+			"start_state": "q0",
+			"transitions": [["q0", "a", null, "q0", "a"], ["q0", "b", "a", "q1", null], ["q1", "b", "a", "q1", null]],
+			"eos": "$",
+			"acceptance": "final_state"
+		}`)},
+		{"empty stack acceptance", []byte(`{
+			"name": "a^n b^n with n >= 1",
+			"states": ["q0", "q1"],
+			"input_alphabet": ["a", "b"],
+			"stack_alphabet": ["a"],
+			"accepting_states": [],
+			"start_state": "q0",
+			"transitions": [["q0", "a", null, "q0", "a"], ["q0", "b", "a", "q1", null], ["q1", "b", "a", "q1", null]],
+			"eos": "$",
+			"acceptance": "empty_stack"
+		}`)},
+		{"pop any top", []byte(`{
+			"name": "a^n b, n >= 1",
+			"states": ["q", "p"],
+			"input_alphabet": ["a", "b"],
+			"stack_alphabet": ["X"],
+			"accepting_states": ["p"],
+			"start_state": "q",
+			"transitions": [["q", "a", null, "q", "X"], ["q", "b", null, "p", null]],
+			"eos": "$",
+			"acceptance": "final_state"
+		}`)},
+	} {
+		assertGrammarRoundTrip(t, test.name, loadSpecification(t, test.specification), 7)
+	}
+}
@@ -0,0 +1,174 @@
+package pda
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/**
+Tokenizer tells how EvaluateInput splits an input token stream into input symbols. Type is whitespace (default), character or lexer,
+a lexer maps the lexemes matched by its rules to input symbols.
+*/
+type Tokenizer struct {
+	Type  string      `json:"type"`
+	Rules []LexerRule `json:"rules,omitempty"`
+}
+
+/**
+LexerRule is a rule of a lexer tokenizer, the lexeme matched by pattern becomes the input symbol Symbol, or is dropped when Skip is set.
+*/
+type LexerRule struct {
+	Pattern string `json:"pattern"`
+	Symbol  string `json:"symbol,omitempty"`
+	Skip    bool   `json:"skip,omitempty"`
+}
+
+/**
+Token is an input symbol read from an input token stream together with the text it was read from and its byte offset.
+*/
+type Token struct {
+	Symbol string `json:"symbol"`
+	Lexeme string `json:"lexeme"`
+	Offset int    `json:"offset"`
+}
+
+/**
+Tokenize splits input into tokens with the tokenizer of the PDA.
+*/
+func (pdaProcessor *PDAProcessor) Tokenize(input string) ([]Token, error) {
+	switch pdaProcessor.tokenizerType() {
+	case PDA_TOKENIZER_CHARACTER:
+		return pdaProcessor.tokenizeCharacters(input), nil
+	case PDA_TOKENIZER_LEXER:
+		return pdaProcessor.tokenizeLexemes(input)
+	}
+	return tokenizeWhitespace(input), nil
+}
+
+func (pdaProcessor *PDAProcessor) tokenizerType() string {
+	if pdaProcessor.Tokenizer == nil || pdaProcessor.Tokenizer.Type == "" {
+		return PDA_TOKENIZER_WHITESPACE
+	}
+	return pdaProcessor.Tokenizer.Type
+}
+
+/**
+every whitespace separated word is a token.
+*/
+func tokenizeWhitespace(input string) []Token {
+	var tokens []Token
+	offset := 0
+	for _, word := range strings.Fields(input) {
+		offset += strings.Index(input[offset:], word)
+		tokens = append(tokens, Token{Symbol: word, Lexeme: word, Offset: offset})
+		offset += len(word)
+	}
+	return tokens
+}
+
+/**
+every character is a token, whitespace is skipped unless it is an input symbol.
+*/
+func (pdaProcessor *PDAProcessor) tokenizeCharacters(input string) []Token {
+	var tokens []Token
+	for offset, character := range input {
+		symbol := string(character)
+		if unicode.IsSpace(character) && !findInArray(pdaProcessor.InputAlphabet, symbol) {
+			continue
+		}
+		tokens = append(tokens, Token{Symbol: symbol, Lexeme: symbol, Offset: offset})
+	}
+	return tokens
+}
+
+/**
+repeatedly take the longest lexeme matched by any rule, the first rule wins between matches of the same length.
+*/
+func (pdaProcessor *PDAProcessor) tokenizeLexemes(input string) ([]Token, error) {
+	if pdaProcessor.lexerPatterns == nil {
+		patterns, diagnostics := compileLexerRules(pdaProcessor.Tokenizer.Rules)
+		if len(diagnostics) > 0 {
+			return nil, &ValidationError{Diagnostics: diagnostics}
+		}
+		pdaProcessor.lexerPatterns = patterns
+	}
+
+	var tokens []Token
+	for offset := 0; offset < len(input); {
+		rule, length := -1, 0
+		for i, pattern := range pdaProcessor.lexerPatterns {
+			if match := pattern.FindStringIndex(input[offset:]); match != nil && match[1] > length {
+				rule, length = i, match[1]
+			}
+		}
+		if rule == -1 {
+			character, _ := utf8.DecodeRuneInString(input[offset:])
+			return tokens, fmt.Errorf("no lexer rule matches the input at offset %d, %q", offset, character)
+		}
+		if !pdaProcessor.Tokenizer.Rules[rule].Skip {
+			tokens = append(tokens, Token{Symbol: pdaProcessor.Tokenizer.Rules[rule].Symbol, Lexeme: input[offset : offset+length], Offset: offset})
+		}
+		offset += length
+	}
+	return tokens, nil
+}
+
+/**
+compile the patterns anchored at the start of the remaining input, a diagnostic is returned for each invalid pattern.
+*/
+func compileLexerRules(rules []LexerRule) ([]*regexp.Regexp, []Diagnostic) {
+	var diagnostics []Diagnostic
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		pattern, err := regexp.Compile(`^(?:` + rule.Pattern + `)`)
+		if err != nil {
+			diagnostics = append(diagnostics, Diagnostic{Path: fmt.Sprintf("tokenizer.rules[%d].pattern", i), Code: PDA_DIAGNOSTIC_INVALID_VALUE, Message: err.Error()})
+			continue
+		}
+		patterns[i] = pattern
+	}
+	return patterns, diagnostics
+}
+
+/**
+check the tokenizer section of the specification.
+*/
+func (pdaProcessor *PDAProcessor) validateTokenizer() []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(path string, code string, message string) {
+		diagnostics = append(diagnostics, Diagnostic{Path: path, Code: code, Message: message})
+	}
+
+	switch pdaProcessor.tokenizerType() {
+	case PDA_TOKENIZER_WHITESPACE:
+	case PDA_TOKENIZER_CHARACTER:
+		for i, symbol := range pdaProcessor.InputAlphabet {
+			if utf8.RuneCountInString(symbol) != 1 {
+				report(fmt.Sprintf("input_alphabet[%d]", i), PDA_DIAGNOSTIC_INVALID_VALUE, fmt.Sprintf("input symbol %q is not a single character so the character tokenizer never reads it", symbol))
+			}
+		}
+	case PDA_TOKENIZER_LEXER:
+		if len(pdaProcessor.Tokenizer.Rules) == 0 {
+			report("tokenizer.rules", PDA_DIAGNOSTIC_REQUIRED, "lexer tokenizer needs at least one rule")
+		}
+		_, patternDiagnostics := compileLexerRules(pdaProcessor.Tokenizer.Rules)
+		diagnostics = append(diagnostics, patternDiagnostics...)
+		for i, rule := range pdaProcessor.Tokenizer.Rules {
+			path := fmt.Sprintf("tokenizer.rules[%d]", i)
+			if rule.Pattern == "" {
+				report(path+".pattern", PDA_DIAGNOSTIC_REQUIRED, "lexer rule pattern cannot be empty")
+			}
+			if rule.Skip && rule.Symbol != "" {
+				report(path+".symbol", PDA_DIAGNOSTIC_INVALID_VALUE, "lexer rule either skips its lexeme or maps it to a symbol")
+			} else if !rule.Skip && !findInArray(pdaProcessor.InputAlphabet, rule.Symbol) {
+				report(path+".symbol", PDA_DIAGNOSTIC_UNDECLARED_INPUT_SYMBOL, fmt.Sprintf("symbol %q is not in the input alphabet", rule.Symbol))
+			}
+		}
+	default:
+		report("tokenizer.type", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA tokenizer type should be one of whitespace, character or lexer")
+	}
+	return diagnostics
+}
@@ -0,0 +1,107 @@
+package pda
+
+import (
+	"fmt"
+)
+
+/**
+TraceStep is one move of the PDA. Position is the position of the consumed token, epsilon moves (empty Token) carry the position
+of the last consumed token, -1 before the first one. Transition is the index of the rule in the specification.
+*/
+type TraceStep struct {
+	Position   int      `json:"position"`
+	Token      string   `json:"token"`
+	Lexeme     string   `json:"lexeme,omitempty"`
+	FromState  string   `json:"from_state"`
+	ToState    string   `json:"to_state"`
+	StackTop   string   `json:"stack_top"`
+	Popped     string   `json:"popped"`
+	Pushed     []string `json:"pushed"`
+	Output     string   `json:"output,omitempty"`
+	Transition int      `json:"transition"`
+	Clock      int      `json:"clock"`
+}
+
+func (step TraceStep) String() string {
+	token := fmt.Sprintf("%q", step.Token)
+	if step.Token == "" {
+		token = "epsilon"
+	} else if step.Lexeme != "" && step.Lexeme != step.Token {
+		token = fmt.Sprintf("%q (%q)", step.Token, step.Lexeme)
+	}
+	output := ""
+	if step.Output != "" {
+		output = fmt.Sprintf(", output %q", step.Output)
+	}
+	return fmt.Sprintf("position %d, %s: %q => %q, stack top %q, popped %q, pushed %q%s (transitions[%d], clock %d)",
+		step.Position, token, step.FromState, step.ToState, step.StackTop, step.Popped, step.Pushed, output, step.Transition, step.Clock)
+}
+
+/**
+immutable list of the moves that led to a configuration, newest first. Configurations branching from the same history share it.
+Only what can not be looked up from the transition is kept, a trace is recorded for every token consumed.
+*/
+type traceNode struct {
+	transition int
+	position   int
+	clock      int
+	stackTop   string
+	previous   *traceNode
+	length     int
+}
+
+func (trace *traceNode) append(transition int, position int, clock int, stackTop string) *traceNode {
+	length := 1
+	if trace != nil {
+		length = trace.length + 1
+	}
+	return &traceNode{transition: transition, position: position, clock: clock, stackTop: stackTop, previous: trace, length: length}
+}
+
+/**
+return the moves oldest first, an empty trace returns an empty slice.
+*/
+func (trace *traceNode) steps(transitions []Transition) []TraceStep {
+	length := 0
+	if trace != nil {
+		length = trace.length
+	}
+	steps := make([]TraceStep, length)
+	for node, i := trace, length-1; node != nil; node, i = node.previous, i-1 {
+		transition := transitions[node.transition]
+		popped := transition.Pop
+		if transition.PopAny {
+			popped = node.stackTop
+		}
+		steps[i] = TraceStep{
+			Position:   node.position,
+			Token:      transition.Input,
+			FromState:  transition.State,
+			ToState:    transition.NextState,
+			StackTop:   node.stackTop,
+			Popped:     popped,
+			Pushed:     transition.Push,
+			Output:     transition.Output,
+			Transition: node.transition,
+			Clock:      node.clock,
+		}
+	}
+	return steps
+}
+
+/**
+return the output symbols written by the moves, the outputs of their transitions in the order taken. Transitions
+without output write nothing.
+*/
+func (trace *traceNode) output(transitions []Transition) []string {
+	outputs := []string{}
+	for node := trace; node != nil; node = node.previous {
+		if output := transitions[node.transition].Output; output != "" {
+			outputs = append(outputs, output)
+		}
+	}
+	for i, j := 0, len(outputs)-1; i < j; i, j = i+1, j-1 {
+		outputs[i], outputs[j] = outputs[j], outputs[i]
+	}
+	return outputs
+}
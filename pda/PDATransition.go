@@ -0,0 +1,124 @@
+package pda
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+/**
+Transition is a PDA transition "in state State reading Input with Pop on top of the stack, pop it, push Push and move to NextState".
+An empty Input is an epsilon move and an empty Pop leaves the stack untouched before pushing, unless PopAny is set: the
+transition then pops whatever symbol is on top of the stack and does not apply to an empty stack.
+Push lists the symbols top first, so ["A", "B"] leaves "A" on top of "B"; an empty Push only pops.
+Output, when given, is written to the output of the PDA whenever the transition is taken.
+*/
+type Transition struct {
+	State     string   `json:"state"`
+	Input     string   `json:"input"`
+	Pop       string   `json:"pop"`
+	PopAny    bool     `json:"pop_any,omitempty"`
+	NextState string   `json:"next_state"`
+	Push      []string `json:"push"`
+	Output    string   `json:"output,omitempty"`
+	// why the transition could not be read, reported by validate() instead of failing the whole specification
+	malformed string
+}
+
+/**
+UnmarshalJSON reads a transition written either as an object or in the compact array form
+[current_state, current_input, current_stack_top, next_state, to_be_stack_top], where to_be_stack_top is
+pushed on top of current_stack_top or, when null, current_stack_top is popped. A null current_stack_top together with a
+null to_be_stack_top pops whatever symbol is on top of the stack, as it always did.
+*/
+func (transition *Transition) UnmarshalJSON(data []byte) error {
+	if !strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		// alias drops the methods so that decoding the object does not recurse
+		type transitionObject Transition
+		if err := json.Unmarshal(data, (*transitionObject)(transition)); err != nil {
+			*transition = Transition{malformed: "transition should be an array or an object: " + err.Error()}
+		}
+		return nil
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	if len(elements) != 5 {
+		*transition = Transition{malformed: fmt.Sprintf("transition has %d elements, it should have 5 elements [current_state, current_input, current_stack_top, next_state, to_be_stack_top]", len(elements))}
+		return nil
+	}
+	fields := make([]string, len(elements))
+	for i, element := range elements {
+		if err := json.Unmarshal(element, &fields[i]); err != nil {
+			*transition = Transition{malformed: fmt.Sprintf("transition element %d should be a string or null", i)}
+			return nil
+		}
+	}
+
+	*transition = Transition{State: fields[0], Input: fields[1], Pop: fields[2], NextState: fields[3]}
+	if fields[2] == "" && fields[4] == "" {
+		transition.PopAny = true
+	} else if fields[4] != "" {
+		transition.Push = []string{fields[4]}
+		if fields[2] != "" {
+			// the compact form keeps the stack top and pushes on top of it
+			transition.Push = append(transition.Push, fields[2])
+		}
+	}
+	return nil
+}
+
+/**
+MarshalJSON writes the transition back in the compact array form whenever it can be expressed in it, so existing specifications keep their shape.
+*/
+func (transition Transition) MarshalJSON() ([]byte, error) {
+	var toBeStackTop interface{}
+	compact := false
+	switch {
+	case transition.Output != "":
+		// the compact form has no place for the output
+	case transition.PopAny:
+		compact = len(transition.Push) == 0
+	case transition.Pop == "" && len(transition.Push) == 0:
+		// in the compact form [.., null, .., null] pops any stack top, a move leaving the stack alone is an object
+	case len(transition.Push) == 0:
+		compact = true
+	case transition.Pop == "" && len(transition.Push) == 1:
+		toBeStackTop, compact = transition.Push[0], true
+	case transition.Pop != "" && len(transition.Push) == 2 && transition.Push[1] == transition.Pop:
+		toBeStackTop, compact = transition.Push[0], true
+	}
+
+	if !compact {
+		type transitionObject Transition
+		return json.Marshal(transitionObject(transition))
+	}
+	return json.Marshal([]interface{}{transition.State, nullIfEmpty(transition.Input), nullIfEmpty(transition.Pop), transition.NextState, toBeStackTop})
+}
+
+func (transition Transition) String() string {
+	pop := fmt.Sprintf("%q", transition.Pop)
+	if transition.PopAny {
+		pop = "any"
+	}
+	if transition.Output != "" {
+		return fmt.Sprintf("%q, %q, %s ----> %q, %q / %q", transition.State, transition.Input, pop, transition.NextState, transition.Push, transition.Output)
+	}
+	return fmt.Sprintf("%q, %q, %s ----> %q, %q", transition.State, transition.Input, pop, transition.NextState, transition.Push)
+}
+
+/**
+tell whether the transition inspects the stack top, i.e. pops a given symbol or any symbol.
+*/
+func (transition Transition) popsStack() bool {
+	return transition.Pop != "" || transition.PopAny
+}
+
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,79 @@
+package pda
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompactTransitionRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		compact    string
+		transition Transition
+	}{
+		{`["q","a",null,"p","X"]`, Transition{State: "q", Input: "a", NextState: "p", Push: []string{"X"}}},
+		{`["q","a","Y","p","X"]`, Transition{State: "q", Input: "a", Pop: "Y", NextState: "p", Push: []string{"X", "Y"}}},
+		{`["q","a","Y","p",null]`, Transition{State: "q", Input: "a", Pop: "Y", NextState: "p"}},
+		{`["q",null,null,"p",null]`, Transition{State: "q", PopAny: true, NextState: "p"}},
+	} {
+		var transition Transition
+		if err := json.Unmarshal([]byte(test.compact), &transition); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(transition, test.transition) {
+			t.Fatalf("%s decoded to %+v, want %+v", test.compact, transition, test.transition)
+		}
+		encoded, err := json.Marshal(transition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(encoded) != test.compact {
+			t.Fatalf("%s encoded back to %s", test.compact, encoded)
+		}
+	}
+}
+
+func TestStackNeutralTransitionIsNotCompact(t *testing.T) {
+	encoded, err := json.Marshal(Transition{State: "q", Input: "a", NextState: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transition Transition
+	if err := json.Unmarshal(encoded, &transition); err != nil {
+		t.Fatal(err)
+	}
+	if transition.PopAny || transition.Pop != "" || len(transition.Push) != 0 {
+		t.Fatalf("%s decoded to %+v, which changes the stack", encoded, transition)
+	}
+}
+
+func TestCompactTransitionPopsAnyTop(t *testing.T) {
+	pdaProcessor := loadSpecification(t, []byte(`{
+		"name": "pop any",
+		"states": ["q", "p"],
+		"input_alphabet": ["a", "b"],
+		"stack_alphabet": ["X"],
+		"accepting_states": ["p"],
+		"start_state": "q",
+		"transitions": [
+			["q", "a", null, "q", "X"],
+			["q", "b", null, "p", null]
+		],
+		"eos": "$"
+	}`))
+	if _, _, err := pdaProcessor.EvaluateInput("a b"); err != nil {
+		t.Fatal(err)
+	}
+	if !pdaProcessor.IsAccepted() || pdaProcessor.Stack.Len() != 0 {
+		t.Fatalf("a b ended with stack %v, accepted %t", pdaProcessor.Peek(10), pdaProcessor.IsAccepted())
+	}
+
+	// with nothing on the stack there is no top to pop
+	pdaProcessor.Reset()
+	if _, _, err := pdaProcessor.EvaluateInput("b"); err != nil {
+		t.Fatal(err)
+	}
+	if pdaProcessor.IsAccepted() {
+		t.Fatal("b was accepted with an empty stack")
+	}
+}
@@ -0,0 +1,165 @@
+package pda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+Diagnostic is a single problem found in a PDA specification; path points at the offending field, e.g. "transitions[3].next_state".
+*/
+type Diagnostic struct {
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+/**
+ValidationError is the error returned when a PDA specification does not pass validation, carries every diagnostic found.
+*/
+type ValidationError struct {
+	Diagnostics []Diagnostic
+}
+
+func (validationError *ValidationError) Error() string {
+	messages := make([]string, len(validationError.Diagnostics))
+	for i, diagnostic := range validationError.Diagnostics {
+		messages[i] = diagnostic.Path + ": " + diagnostic.Message
+	}
+	return "invalid PDA specification, " + strings.Join(messages, "; ")
+}
+
+/**
+DecodeDiagnostic turns the error of decoding a specification into a diagnostic. A value of the wrong type points at its
+field, a body which is no JSON at all has an empty path.
+*/
+func DecodeDiagnostic(err error) Diagnostic {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return Diagnostic{Path: typeError.Field, Code: PDA_DIAGNOSTIC_INVALID_JSON, Message: fmt.Sprintf("expected %s, got JSON %s", typeError.Type, typeError.Value)}
+	}
+	return Diagnostic{Path: "", Code: PDA_DIAGNOSTIC_INVALID_JSON, Message: err.Error()}
+}
+
+/**
+Validate statically checks the specification and returns the list of problems found, empty when the specification is valid.
+*/
+func (pdaProcessor *PDAProcessor) Validate() []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(path string, code string, message string) {
+		diagnostics = append(diagnostics, Diagnostic{Path: path, Code: code, Message: message})
+	}
+
+	if pdaProcessor.Name == "" {
+		report("name", PDA_DIAGNOSTIC_REQUIRED, "PDA Name field cannot be empty")
+	}
+	if pdaProcessor.Eos == "" {
+		report("eos", PDA_DIAGNOSTIC_REQUIRED, "PDA end of stream field cannot be empty")
+	}
+	if len(pdaProcessor.States) == 0 {
+		report("states", PDA_DIAGNOSTIC_REQUIRED, "PDA states cannot be empty")
+	}
+	if len(pdaProcessor.InputAlphabet) == 0 {
+		report("input_alphabet", PDA_DIAGNOSTIC_REQUIRED, "PDA input alphabets field cannot be empty")
+	}
+	if len(pdaProcessor.StackAlphabet) == 0 {
+		report("stack_alphabet", PDA_DIAGNOSTIC_REQUIRED, "PDA stack alphabets field cannot be empty")
+	}
+
+	checkSymbols := func(field string, symbols []string) {
+		seen := make(map[string]bool)
+		for i, symbol := range symbols {
+			path := fmt.Sprintf("%s[%d]", field, i)
+			if symbol == "" {
+				report(path, PDA_DIAGNOSTIC_REQUIRED, "empty symbol in "+field)
+			} else if seen[symbol] {
+				report(path, PDA_DIAGNOSTIC_DUPLICATE, fmt.Sprintf("%q is declared more than once in %s", symbol, field))
+			}
+			seen[symbol] = true
+		}
+	}
+	checkSymbols("states", pdaProcessor.States)
+	checkSymbols("input_alphabet", pdaProcessor.InputAlphabet)
+	checkSymbols("stack_alphabet", pdaProcessor.StackAlphabet)
+
+	isState := func(state string) bool { return findInArray(pdaProcessor.States, state) }
+	isStackSymbol := func(symbol string) bool {
+		return symbol == pdaProcessor.Eos || findInArray(pdaProcessor.StackAlphabet, symbol)
+	}
+
+	if pdaProcessor.StartState == "" {
+		report("start_state", PDA_DIAGNOSTIC_REQUIRED, "PDA start state cannot be empty")
+	} else if !isState(pdaProcessor.StartState) {
+		report("start_state", PDA_DIAGNOSTIC_UNDECLARED_STATE, fmt.Sprintf("start state %q is not one of the states", pdaProcessor.StartState))
+	}
+
+	if len(pdaProcessor.AcceptingStates) == 0 && pdaProcessor.Acceptance != PDA_ACCEPTANCE_EMPTY_STACK {
+		report("accepting_states", PDA_DIAGNOSTIC_REQUIRED, "PDA accepting states field cannot be empty")
+	}
+	for i, state := range pdaProcessor.AcceptingStates {
+		if !isState(state) {
+			report(fmt.Sprintf("accepting_states[%d]", i), PDA_DIAGNOSTIC_UNDECLARED_STATE, fmt.Sprintf("accepting state %q is not one of the states", state))
+		}
+	}
+
+	switch pdaProcessor.Mode {
+	case "", PDA_MODE_DETERMINISTIC, PDA_MODE_NONDETERMINISTIC:
+	default:
+		report("mode", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA mode field should be one of deterministic or nondeterministic")
+	}
+	switch pdaProcessor.Acceptance {
+	case "", PDA_ACCEPTANCE_BOTH, PDA_ACCEPTANCE_FINAL_STATE, PDA_ACCEPTANCE_EMPTY_STACK:
+	default:
+		report("acceptance", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA acceptance field should be one of final_state, empty_stack or both")
+	}
+	if pdaProcessor.MaxPendingTokens < 0 {
+		report("max_pending_tokens", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA max pending tokens field cannot be negative")
+	}
+	if pdaProcessor.MaxCheckpoints < 0 {
+		report("max_checkpoints", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA max checkpoints field cannot be negative")
+	}
+	if pdaProcessor.MaxStackDepth < 0 {
+		report("max_stack_depth", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA max stack depth field cannot be negative")
+	}
+	if pdaProcessor.MaxStepsPerToken < 0 {
+		report("max_steps_per_token", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA max steps per token field cannot be negative")
+	}
+	if pdaProcessor.MaxTotalSteps < 0 {
+		report("max_total_steps", PDA_DIAGNOSTIC_INVALID_VALUE, "PDA max total steps field cannot be negative")
+	}
+
+	diagnostics = append(diagnostics, pdaProcessor.validateTokenizer()...)
+
+	for i, transition := range pdaProcessor.Transitions {
+		path := fmt.Sprintf("transitions[%d]", i)
+		if transition.malformed != "" {
+			report(path, PDA_DIAGNOSTIC_MALFORMED_TRANSITION, transition.malformed)
+			continue
+		}
+		if !isState(transition.State) {
+			report(path+".state", PDA_DIAGNOSTIC_UNDECLARED_STATE, fmt.Sprintf("state %q is not one of the states", transition.State))
+		}
+		if !isState(transition.NextState) {
+			report(path+".next_state", PDA_DIAGNOSTIC_UNDECLARED_STATE, fmt.Sprintf("next state %q is not one of the states", transition.NextState))
+		}
+		if transition.Input != "" && !findInArray(pdaProcessor.InputAlphabet, transition.Input) {
+			report(path+".input", PDA_DIAGNOSTIC_UNDECLARED_INPUT_SYMBOL, fmt.Sprintf("input %q is not in the input alphabet", transition.Input))
+		}
+		if transition.PopAny && transition.Pop != "" {
+			report(path+".pop_any", PDA_DIAGNOSTIC_INVALID_VALUE, "transition can not both pop a given stack top and pop any stack top")
+		}
+		if transition.Pop != "" && !isStackSymbol(transition.Pop) {
+			report(path+".pop", PDA_DIAGNOSTIC_UNDECLARED_STACK_SYMBOL, fmt.Sprintf("stack top %q is not in the stack alphabet", transition.Pop))
+		}
+		for j, symbol := range transition.Push {
+			// the compact array form pushes the stack top back, which is already reported above
+			if !isStackSymbol(symbol) && symbol != transition.Pop {
+				report(fmt.Sprintf("%s.push[%d]", path, j), PDA_DIAGNOSTIC_UNDECLARED_STACK_SYMBOL, fmt.Sprintf("pushed symbol %q is not in the stack alphabet", symbol))
+			}
+		}
+	}
+
+	return diagnostics
+}
@@ -5,11 +5,11 @@ rm -rf pda-processor
 
 if [ ! $# -eq 1 ]; then
   # build project
-  go build
+  go build -o pda-processor ./cmd/pda-processor
 else
   if [[ $1 =~ ^[0-9]{4}[:.,-]?$ ]]; then
     # build with using custom port
-    go build -ldflags "-X main.port=$1"
+    go build -o pda-processor -ldflags "-X main.port=$1" ./cmd/pda-processor
   else
     echo "invalid port number: $1"
     exit 1
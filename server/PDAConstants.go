@@ -0,0 +1,15 @@
+package server
+
+import "time"
+
+const PDA_FILES_BASE_FOLDER string = "./PDAFiles"
+const PDA_FILE_NAME_PREFIX string = "testPdaSpecs"
+const PDA_FILE_NAME_POSTFIX string = ".json"
+
+const PDA_UPLOAD_GRAMMAR_MAX_PRODUCTIONS int = 100000 // bound on the grammar built for the emptiness check of an upload
+
+const PDA_SESSION_ID_BYTES int = 16                           // random bytes of a session id, 128 bits
+const PDA_MAX_SESSIONS int = 10000                            // default for the most sessions open at the same time, PDA_MAX_SESSIONS
+const PDA_SESSION_IDLE_TTL time.Duration = 30 * time.Minute   // default for the time a session lives without requests, PDA_SESSION_IDLE_TTL
+const PDA_SESSION_ABSOLUTE_TTL time.Duration = 24 * time.Hour // default for the time a session lives at most, PDA_SESSION_TTL
+const PDA_SESSION_REAP_INTERVAL time.Duration = time.Minute   // interval at which expired sessions are removed
@@ -0,0 +1,388 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/pravin-gayal/pda-processor/pda"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/**
+most sessions open at the same time, 0 for no limit.
+*/
+var sessionLimit = PDA_MAX_SESSIONS
+
+/**
+time after which a session expires, since its last request and since its creation, 0 for never.
+*/
+var sessionIdleTTL = PDA_SESSION_IDLE_TTL
+var sessionAbsoluteTTL = PDA_SESSION_ABSOLUTE_TTL
+
+/**
+error returned when a session is created while the session limit is reached.
+*/
+var errSessionLimitReached = errors.New("session limit reached")
+
+/**
+ConfigureSessions sets the most sessions open at the same time and their idle and absolute time to live from the given
+values, as read from PDA_MAX_SESSIONS, PDA_SESSION_IDLE_TTL and PDA_SESSION_TTL. The time to live is a duration like
+30m or 12h, 0 for no limit like for the number of sessions. Empty values keep the defaults. It has to be called before
+Run.
+*/
+func ConfigureSessions(maxSessions string, idleTTL string, absoluteTTL string) error {
+	if maxSessions != "" {
+		limit, err := strconv.Atoi(maxSessions)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("invalid session limit %q, should be a non-negative integer", maxSessions)
+		}
+		sessionLimit = limit
+	}
+	if idleTTL != "" {
+		ttl, err := parseTTL(idleTTL)
+		if err != nil {
+			return fmt.Errorf("invalid session idle time to live %q, should be a duration like 30m", idleTTL)
+		}
+		sessionIdleTTL = ttl
+	}
+	if absoluteTTL != "" {
+		ttl, err := parseTTL(absoluteTTL)
+		if err != nil {
+			return fmt.Errorf("invalid session time to live %q, should be a duration like 24h", absoluteTTL)
+		}
+		sessionAbsoluteTTL = ttl
+	}
+	return nil
+}
+
+func parseTTL(value string) (time.Duration, error) {
+	if value == "0" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < 0 {
+		return 0, errors.New("invalid duration")
+	}
+	return ttl, nil
+}
+
+/**
+PDARegistry holds the PDAs available at the server. It is safe for concurrent use, every PDA is stored and handed out
+as a deep copy made by Clone, so callers never share the specification held by the registry and may change what they
+get.
+*/
+type PDARegistry struct {
+	mutex sync.RWMutex
+	pdas  []pda.PDAProcessor
+}
+
+/**
+SessionRegistry holds the PDA of every client session. It is safe for concurrent use, and the requests to one session
+are serialized by the lock of that session, so two requests never evaluate the same PDA at the same time.
+*/
+type SessionRegistry struct {
+	mutex       sync.RWMutex
+	sessions    map[string]*session
+	maxSessions int
+	idleTTL     time.Duration
+	absoluteTTL time.Duration
+}
+
+/**
+SessionInfo describes an open session as listed to administrators. ExpiresAt is when the session expires unless it is
+used before, it is omitted when sessions never expire.
+*/
+type SessionInfo struct {
+	SessionId    string     `json:"session_id"`
+	PdaId        int        `json:"pda_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastActivity time.Time  `json:"last_activity"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+}
+
+/**
+PDA of a client session together with the lock serializing the requests to it. The lock is kept next to the PDA rather
+than in PDAProcessor so that the PDA can still be copied by value.
+*/
+type session struct {
+	mutex        sync.Mutex
+	pdaId        int
+	pdaProcessor *pda.PDAProcessor
+	removed      bool
+	createdAt    time.Time
+	lastActivity atomic.Int64 // unix nanoseconds of the last request, read without the lock of the session
+}
+
+func newPDARegistry() *PDARegistry {
+	return &PDARegistry{}
+}
+
+func newSessionRegistry(maxSessions int, idleTTL time.Duration, absoluteTTL time.Duration) *SessionRegistry {
+	return &SessionRegistry{sessions: make(map[string]*session), maxSessions: maxSessions, idleTTL: idleTTL, absoluteTTL: absoluteTTL}
+}
+
+/**
+return a copy of the list of available PDAs.
+*/
+func (registry *PDARegistry) list() []pda.PDAProcessor {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	pdas := make([]pda.PDAProcessor, len(registry.pdas))
+	for i := range registry.pdas {
+		pdas[i] = registry.pdas[i].Clone()
+	}
+	return pdas
+}
+
+/**
+return a copy of the PDA with given id, false when there is none.
+*/
+func (registry *PDARegistry) get(pdaId int) (pda.PDAProcessor, bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	index := registry.indexOf(pdaId)
+	if index == -1 {
+		return pda.PDAProcessor{}, false
+	}
+	return registry.pdas[index].Clone(), true
+}
+
+/**
+add the PDA unless its id is already used. persist, when given, is run while the id is held so that two PDAs created
+with the same id at the same time can not overwrite each other's specification file; the PDA is only added when it
+succeeds.
+*/
+func (registry *PDARegistry) add(pdaProcessor pda.PDAProcessor, persist func() error) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.indexOf(pdaProcessor.ID) != -1 {
+		return errors.New("ID already used")
+	}
+	if persist != nil {
+		if err := persist(); err != nil {
+			return err
+		}
+	}
+	registry.pdas = append(registry.pdas, pdaProcessor.Clone())
+	return nil
+}
+
+/**
+remove the PDA with given id, returns false when there is none. unpersist, when given, is run before the PDA is
+removed, which is kept when it fails.
+*/
+func (registry *PDARegistry) remove(pdaId int, unpersist func() error) (bool, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	index := registry.indexOf(pdaId)
+	if index == -1 {
+		return false, nil
+	}
+	if unpersist != nil {
+		if err := unpersist(); err != nil {
+			return true, err
+		}
+	}
+	registry.pdas = append(registry.pdas[:index], registry.pdas[index+1:]...)
+	return true, nil
+}
+
+/**
+index of the PDA with given id, -1 when there is none. The caller holds the lock of the registry.
+*/
+func (registry *PDARegistry) indexOf(pdaId int) int {
+	for i, pdaProcessor := range registry.pdas {
+		if pdaProcessor.ID == pdaId {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+add the PDA of a new session, returns false when the session id is already used and errSessionLimitReached when the
+registry holds as many sessions as it may.
+*/
+func (registry *SessionRegistry) add(sessionId string, pdaProcessor *pda.PDAProcessor) (bool, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.maxSessions > 0 && len(registry.sessions) >= registry.maxSessions {
+		return false, fmt.Errorf("%w, at most %d sessions can be open", errSessionLimitReached, registry.maxSessions)
+	}
+	if _, containsKey := registry.sessions[sessionId]; containsKey {
+		return false, nil
+	}
+	session := &session{pdaId: pdaProcessor.ID, pdaProcessor: pdaProcessor, createdAt: time.Now()}
+	session.lastActivity.Store(session.createdAt.UnixNano())
+	registry.sessions[sessionId] = session
+	return true, nil
+}
+
+/**
+lock the session and return its PDA, which has to be the PDA with given id. The caller owns the PDA until it calls
+release, requests to the same session wait until then. An expired session is removed instead.
+*/
+func (registry *SessionRegistry) acquire(sessionId string, pdaId int) (*pda.PDAProcessor, func(), error) {
+	registry.mutex.RLock()
+	session, hasSession := registry.sessions[sessionId]
+	registry.mutex.RUnlock()
+	if !hasSession {
+		return nil, nil, errors.New("invalid session")
+	}
+
+	session.mutex.Lock()
+	// the session may have been removed while waiting for its lock
+	if session.removed {
+		session.mutex.Unlock()
+		return nil, nil, errors.New("invalid session")
+	}
+	if registry.expired(session, time.Now()) {
+		session.mutex.Unlock()
+		if registry.remove(sessionId) {
+			session.pdaProcessor.Logger().Info("session expired")
+		}
+		return nil, nil, errors.New("session expired")
+	}
+	if pdaId != session.pdaId {
+		session.mutex.Unlock()
+		return nil, nil, errors.New("invalid pda id for given session")
+	}
+	session.lastActivity.Store(time.Now().UnixNano())
+	return session.pdaProcessor, session.mutex.Unlock, nil
+}
+
+/**
+remove the session, waiting for the request holding it to finish. Returns false when there is no such session.
+*/
+func (registry *SessionRegistry) remove(sessionId string) bool {
+	registry.mutex.Lock()
+	session, hasSession := registry.sessions[sessionId]
+	delete(registry.sessions, sessionId)
+	registry.mutex.Unlock()
+	if !hasSession {
+		return false
+	}
+
+	session.mutex.Lock()
+	session.removed = true
+	session.mutex.Unlock()
+	return true
+}
+
+/**
+remove every session of the PDA with given id and return how many were removed.
+*/
+func (registry *SessionRegistry) removeByPDA(pdaId int) int {
+	return len(registry.removeWhere(func(session *session) bool {
+		return session.pdaId == pdaId
+	}))
+}
+
+/**
+remove every session which expired by now and return the ids of the removed sessions.
+*/
+func (registry *SessionRegistry) removeExpired(now time.Time) []string {
+	return registry.removeWhere(func(session *session) bool {
+		return registry.expired(session, now)
+	})
+}
+
+/**
+remove every session matching the condition, waiting for the requests holding them to finish, and return their ids.
+*/
+func (registry *SessionRegistry) removeWhere(condition func(session *session) bool) []string {
+	registry.mutex.Lock()
+	var removedIds []string
+	var removed []*session
+	for sessionId, session := range registry.sessions {
+		if condition(session) {
+			delete(registry.sessions, sessionId)
+			removedIds = append(removedIds, sessionId)
+			removed = append(removed, session)
+		}
+	}
+	registry.mutex.Unlock()
+
+	for _, session := range removed {
+		session.mutex.Lock()
+		session.removed = true
+		session.mutex.Unlock()
+	}
+	return removedIds
+}
+
+/**
+tell whether the session was idle for longer than the idle time to live or is older than the absolute time to live.
+*/
+func (registry *SessionRegistry) expired(session *session, now time.Time) bool {
+	expiresAt, expires := registry.expiresAt(session)
+	return expires && !now.Before(expiresAt)
+}
+
+/**
+time at which the session expires unless it is used before, false when sessions never expire.
+*/
+func (registry *SessionRegistry) expiresAt(session *session) (time.Time, bool) {
+	var expiresAt time.Time
+	expires := false
+	if registry.idleTTL > 0 {
+		expiresAt = time.Unix(0, session.lastActivity.Load()).Add(registry.idleTTL)
+		expires = true
+	}
+	if registry.absoluteTTL > 0 {
+		deadline := session.createdAt.Add(registry.absoluteTTL)
+		if !expires || deadline.Before(expiresAt) {
+			expiresAt = deadline
+		}
+		expires = true
+	}
+	return expiresAt, expires
+}
+
+/**
+list the open sessions, oldest first.
+*/
+func (registry *SessionRegistry) list() []SessionInfo {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	sessions := make([]SessionInfo, 0, len(registry.sessions))
+	for sessionId, session := range registry.sessions {
+		info := SessionInfo{
+			SessionId:    sessionId,
+			PdaId:        session.pdaId,
+			CreatedAt:    session.createdAt,
+			LastActivity: time.Unix(0, session.lastActivity.Load()),
+		}
+		if expiresAt, expires := registry.expiresAt(session); expires {
+			info.ExpiresAt = &expiresAt
+		}
+		sessions = append(sessions, info)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions
+}
+
+/**
+remove expired sessions every interval for as long as the server runs, so that sessions abandoned by their clients do
+not hold on to their PDA.
+*/
+func (registry *SessionRegistry) reap(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for now := range ticker.C {
+		expired := registry.removeExpired(now)
+		if len(expired) > 0 {
+			pda.DefaultLogger().Info("removed expired sessions", "sessions", len(expired))
+		}
+	}
+}
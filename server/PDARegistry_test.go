@@ -0,0 +1,277 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pravin-gayal/pda-processor/pda"
+)
+
+func TestPDARegistryConcurrentAdd(t *testing.T) {
+	registry := newPDARegistry()
+	var wg sync.WaitGroup
+	for id := 1; id <= 50; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if err := registry.add(pda.PDAProcessor{ID: id}, nil); err != nil {
+				t.Error(err)
+			}
+			registry.list()
+			registry.get(id)
+		}(id)
+	}
+	wg.Wait()
+
+	if got := len(registry.list()); got != 50 {
+		t.Fatalf("registry holds %d PDAs, want 50", got)
+	}
+	for id := 1; id <= 50; id++ {
+		if _, exists := registry.get(id); !exists {
+			t.Fatalf("PDA %d was lost", id)
+		}
+	}
+}
+
+func TestPDARegistrySameIdOnlyOnce(t *testing.T) {
+	registry := newPDARegistry()
+	var persisted, succeeded atomic.Int32
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := registry.add(pda.PDAProcessor{ID: 7}, func() error {
+				persisted.Add(1)
+				return nil
+			})
+			if err == nil {
+				succeeded.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if succeeded.Load() != 1 || persisted.Load() != 1 {
+		t.Fatalf("%d creations succeeded and %d were persisted, want 1 and 1", succeeded.Load(), persisted.Load())
+	}
+	if got := len(registry.list()); got != 1 {
+		t.Fatalf("registry holds %d PDAs, want 1", got)
+	}
+}
+
+func TestPDARegistryConcurrentRemove(t *testing.T) {
+	registry := newPDARegistry()
+	for id := 1; id <= 20; id++ {
+		registry.add(pda.PDAProcessor{ID: id}, nil)
+	}
+	var removed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if existed, _ := registry.remove(id, nil); existed {
+				removed.Add(1)
+			}
+		}(i%20 + 1)
+	}
+	wg.Wait()
+
+	if removed.Load() != 20 || len(registry.list()) != 0 {
+		t.Fatalf("removed %d PDAs, %d left", removed.Load(), len(registry.list()))
+	}
+}
+
+func TestPDARegistryHandsOutDeepCopies(t *testing.T) {
+	pdaProcessor := pda.PDAProcessor{}
+	err := pdaProcessor.Load([]byte(`{
+		"ID": 3,
+		"name": "copies",
+		"states": ["q1", "q2"],
+		"input_alphabet": ["0"],
+		"stack_alphabet": ["0"],
+		"accepting_states": ["q2"],
+		"start_state": "q1",
+		"transitions": [["q1", "0", null, "q2", "0"]],
+		"eos": "$",
+		"tokenizer": {"type": "lexer", "rules": [{"pattern": "0", "symbol": "0"}]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := newPDARegistry()
+	if err := registry.add(pdaProcessor, nil); err != nil {
+		t.Fatal(err)
+	}
+	// the caller keeps its own PDA, changing it leaves the registry alone
+	pdaProcessor.States[0] = "changed"
+
+	for _, got := range []pda.PDAProcessor{registry.list()[0], mustGet(t, registry, 3)} {
+		got.States[1] = "changed"
+		got.Transitions[0].Push[0] = "changed"
+		got.Tokenizer.Rules[0].Symbol = "changed"
+		got.PendingTokenQueue[1] = "0"
+		got.Configurations[0].State = "changed"
+	}
+	running := mustGet(t, registry, 3)
+	if _, err := running.Put(0, "0"); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := mustGet(t, registry, 3)
+	if stored.States[0] != "q1" || stored.States[1] != "q2" || stored.Transitions[0].Push[0] != "0" || stored.Tokenizer.Rules[0].Symbol != "0" {
+		t.Fatalf("specification of the stored PDA was changed: %v %v %v", stored.States, stored.Transitions, stored.Tokenizer.Rules)
+	}
+	if len(stored.PendingTokenQueue) != 0 || stored.Configurations[0].State != "q1" || stored.State() != "q1" || stored.LastConsumedPosition != -1 {
+		t.Fatalf("stored PDA was run: state %q, queue %v", stored.State(), stored.PendingTokenQueue)
+	}
+}
+
+func mustGet(t *testing.T, registry *PDARegistry, pdaId int) pda.PDAProcessor {
+	pdaProcessor, exists := registry.get(pdaId)
+	if !exists {
+		t.Fatalf("PDA %d is missing", pdaId)
+	}
+	return pdaProcessor
+}
+
+func TestSessionRegistrySameSessionSerialized(t *testing.T) {
+	registry := newSessionRegistry(0, 0, 0)
+	if added, err := registry.add("session", &pda.PDAProcessor{ID: 1}); !added || err != nil {
+		t.Fatal(added, err)
+	}
+
+	// the PDA is changed without synchronization of its own, the race detector reports any unserialized access
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pdaProcessor, release, err := registry.acquire("session", 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			pdaProcessor.Steps++
+			release()
+		}()
+	}
+	wg.Wait()
+
+	pdaProcessor, release, err := registry.acquire("session", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	if pdaProcessor.Steps != 50 {
+		t.Fatalf("got %d steps, want 50", pdaProcessor.Steps)
+	}
+}
+
+func TestSessionRegistryDifferentSessions(t *testing.T) {
+	registry := newSessionRegistry(0, 0, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(sessionId string) {
+			defer wg.Done()
+			if added, err := registry.add(sessionId, &pda.PDAProcessor{ID: 1}); !added || err != nil {
+				t.Error(added, err)
+				return
+			}
+			for j := 0; j < 20; j++ {
+				pdaProcessor, release, err := registry.acquire(sessionId, 1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				pdaProcessor.Steps++
+				release()
+				registry.list()
+			}
+			if !registry.remove(sessionId) {
+				t.Errorf("session %s was not removed", sessionId)
+			}
+		}("session_" + strconv.Itoa(i))
+	}
+	wg.Wait()
+
+	if got := len(registry.list()); got != 0 {
+		t.Fatalf("%d sessions left", got)
+	}
+}
+
+func TestSessionRegistryRemoveWhileHeld(t *testing.T) {
+	registry := newSessionRegistry(0, 0, 0)
+	registry.add("session", &pda.PDAProcessor{ID: 1})
+	pdaProcessor, release, err := registry.acquire("session", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// remove waits for the request holding the session
+	removed := make(chan bool)
+	go func() { removed <- registry.remove("session") }()
+	pdaProcessor.Steps++
+	release()
+	if !<-removed {
+		t.Fatal("session was not removed")
+	}
+
+	if _, _, err := registry.acquire("session", 1); err == nil {
+		t.Fatal("removed session was acquired")
+	}
+	if registry.remove("session") {
+		t.Fatal("session was removed twice")
+	}
+}
+
+func TestSessionRegistryConcurrentRemoveByPDA(t *testing.T) {
+	registry := newSessionRegistry(0, 0, 0)
+	for i := 0; i < 20; i++ {
+		registry.add("session_"+strconv.Itoa(i), &pda.PDAProcessor{ID: 1 + i%2})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(sessionId string, pdaId int) {
+			defer wg.Done()
+			if pdaProcessor, release, err := registry.acquire(sessionId, pdaId); err == nil {
+				pdaProcessor.Steps++
+				release()
+			}
+		}("session_"+strconv.Itoa(i), 1+i%2)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if removed := registry.removeByPDA(1); removed != 10 {
+			t.Errorf("removed %d sessions, want 10", removed)
+		}
+	}()
+	wg.Wait()
+
+	for _, info := range registry.list() {
+		if info.PdaId == 1 {
+			t.Fatalf("session %s of the removed PDA is left", info.SessionId)
+		}
+	}
+}
+
+func TestSessionRegistryWrongPdaId(t *testing.T) {
+	registry := newSessionRegistry(0, 0, 0)
+	registry.add("session", &pda.PDAProcessor{ID: 1})
+	if _, _, err := registry.acquire("session", 2); err == nil || err.Error() != "invalid pda id for given session" {
+		t.Fatalf("got %v", err)
+	}
+	if _, _, err := registry.acquire("unknown", 1); err == nil || err.Error() != "invalid session" {
+		t.Fatalf("got %v", err)
+	}
+}
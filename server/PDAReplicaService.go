@@ -0,0 +1,189 @@
+package server
+
+import (
+	"errors"
+	"github.com/pravin-gayal/pda-processor/pda"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+type PDAReplicaService struct {
+}
+
+type ReplicaGroup struct {
+	Gid              int              `json:"gid"`
+	GroupName        string           `json:"group_name"`
+	PdaGroupMembers  []string         `json:"pda_members"`
+	PdaCode          int              `json:"pda_code"`
+	PdaSpecification pda.PDAProcessor `json:"pda_specification"`
+}
+
+/**
+replica groups known to the server, guarded by replicaGroupsMutex as they are read and changed by concurrent requests.
+*/
+var availableReplicaGroups []ReplicaGroup
+var replicaGroupsMutex sync.RWMutex
+
+func (replicaService *PDAReplicaService) getAvailableReplicaGroups() []ReplicaGroup {
+	replicaGroupsMutex.RLock()
+	defer replicaGroupsMutex.RUnlock()
+	return append([]ReplicaGroup{}, availableReplicaGroups...)
+}
+
+func (replicaService *PDAReplicaService) createReplicaGroup(gid int, replicaGroup ReplicaGroup) (ReplicaGroup, error) {
+	pda.DefaultLogger().Debug("creating replica group", "gid", gid)
+	if len(replicaGroup.PdaGroupMembers) == 0 {
+		return replicaGroup, errors.New("Group members cannot be empty")
+	}
+	if len(replicaGroup.GroupName) == 0 {
+		return replicaGroup, errors.New("Group name cannot be empty")
+	}
+
+	existingRG := findReplicaGroupById(gid)
+	if existingRG.Gid != 0 {
+		return replicaGroup, errors.New("gid already used")
+	}
+
+	// save specification on base server
+	if _, err := pdaService.createNewPDA(replicaGroup.PdaCode, replicaGroup.PdaSpecification); err != nil {
+		pda.DefaultLogger().Warn("PDA of replica group not created", "gid", gid, "pda_id", replicaGroup.PdaCode, "error", err)
+	}
+	// call pda members to load saved PDA
+	for _, memberUrl := range replicaGroup.PdaGroupMembers {
+		makeHttpGetCall(gid, memberUrl, "/pdas/"+strconv.Itoa(replicaGroup.PdaCode)+"/load")
+	}
+
+	// the members are called without holding the lock, so another request may have taken the gid meanwhile
+	replicaGroupsMutex.Lock()
+	defer replicaGroupsMutex.Unlock()
+	if findReplicaGroupByIdLocked(gid).Gid != 0 {
+		return replicaGroup, errors.New("gid already used")
+	}
+	availableReplicaGroups = append(availableReplicaGroups, replicaGroup)
+	pda.DefaultLogger().Info("created replica group", "gid", gid, "members", len(replicaGroup.PdaGroupMembers))
+	return replicaGroup, nil
+}
+
+func (replicaService *PDAReplicaService) resetReplicaGroup(gid int) error {
+	pda.DefaultLogger().Debug("resetting replica group", "gid", gid)
+	existingRG := findReplicaGroupById(gid)
+	if existingRG.Gid == 0 {
+		return errors.New("invalid gid")
+	}
+	// call pda members to load saved PDA
+	for _, memberUrl := range existingRG.PdaGroupMembers {
+		makeHttpPutCall(gid, memberUrl, "/pdas/"+strconv.Itoa(existingRG.PdaCode)+"/reset")
+	}
+	pda.DefaultLogger().Info("reset replica group", "gid", gid)
+	return nil
+}
+
+func (replicaService *PDAReplicaService) getMembersFromReplicaGroup(gid int) ([]string, error) {
+	pda.DefaultLogger().Debug("getting members of replica group", "gid", gid)
+	existingRG := findReplicaGroupById(gid)
+	if existingRG.Gid == 0 {
+		return existingRG.PdaGroupMembers, errors.New("invalid group id")
+	}
+	return existingRG.PdaGroupMembers, nil
+}
+
+func (replicaService *PDAReplicaService) connectToReplicaGroup(gid int) (string, error) {
+	existingRG := findReplicaGroupById(gid)
+	if existingRG.Gid == 0 {
+		return "", errors.New("invalid group id")
+	}
+	randomMemberIndex := rand.Intn(len(existingRG.PdaGroupMembers))
+	pda.DefaultLogger().Debug("connecting to replica group", "gid", gid, "member", existingRG.PdaGroupMembers[randomMemberIndex])
+	return existingRG.PdaGroupMembers[randomMemberIndex], nil
+}
+
+func (replicaService *PDAReplicaService) deleteReplicaGroup(gid int) error {
+	pda.DefaultLogger().Debug("deleting replica group", "gid", gid)
+	replicaGroupsMutex.Lock()
+	defer replicaGroupsMutex.Unlock()
+	index := -1
+	for i, replicaGroup := range availableReplicaGroups {
+		if replicaGroup.Gid == gid {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		pda.DefaultLogger().Debug("replica group does not exist", "gid", gid)
+		return errors.New("Replica group with specified id does not exist")
+	}
+
+	availableReplicaGroups = append(availableReplicaGroups[:index], availableReplicaGroups[index+1:]...)
+	pda.DefaultLogger().Info("deleted replica group", "gid", gid)
+	return nil
+}
+
+func (replicaService *PDAReplicaService) closeReplicaGroup(gid int) error {
+	pda.DefaultLogger().Debug("closing replica group", "gid", gid)
+	existingRG := findReplicaGroupById(gid)
+	if existingRG.Gid == 0 {
+		return errors.New("invalid gid")
+	}
+	// call pda members to load saved PDA
+	for _, memberUrl := range existingRG.PdaGroupMembers {
+		makeHttpPutCall(gid, memberUrl, "/pdas/"+strconv.Itoa(existingRG.PdaCode)+"/close")
+	}
+	pda.DefaultLogger().Info("closed replica group", "gid", gid)
+	return nil
+}
+
+func (replicaService *PDAReplicaService) addPDAToReplicaGroup(pdaId int, replicaGroup ReplicaGroup) error {
+	existingRG := findReplicaGroupById(replicaGroup.Gid)
+	if existingRG.Gid == 0 {
+		return errors.New("invalid gid")
+	}
+
+	return nil
+}
+
+func findReplicaGroupById(gid int) ReplicaGroup {
+	replicaGroupsMutex.RLock()
+	defer replicaGroupsMutex.RUnlock()
+	return findReplicaGroupByIdLocked(gid)
+}
+
+/**
+find the replica group with given gid while replicaGroupsMutex is already held.
+*/
+func findReplicaGroupByIdLocked(gid int) ReplicaGroup {
+	for _, replicaGroup := range availableReplicaGroups {
+		if replicaGroup.Gid == gid {
+			return replicaGroup
+		}
+	}
+	// return empty struct
+	return ReplicaGroup{}
+}
+
+/**
+call path on a member of the replica group with given gid. A member which can not be reached is logged and skipped, so
+that one member being down does not fail the request to the whole group.
+*/
+func makeHttpGetCall(gid int, memberUrl string, path string) {
+	pda.DefaultLogger().Debug("calling replica group member", "gid", gid, "member", memberUrl, "path", path)
+	resp, err := http.Get(memberUrl + path)
+
+	if err != nil {
+		pda.DefaultLogger().Error("replica group member call failed", "gid", gid, "member", memberUrl, "path", path, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+}
+
+func makeHttpPutCall(gid int, memberUrl string, path string) {
+	pda.DefaultLogger().Debug("calling replica group member", "gid", gid, "member", memberUrl, "path", path)
+	resp, err := http.Get(memberUrl + path)
+
+	if err != nil {
+		pda.DefaultLogger().Error("replica group member call failed", "gid", gid, "member", memberUrl, "path", path, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestReplicaGroupsConcurrentCreateAndDelete(t *testing.T) {
+	pdaService = &PDAService{pdas: newPDARegistry(), sessions: newSessionRegistry(0, 0, 0)}
+	defer func() { pdaService, availableReplicaGroups = nil, nil }()
+	replicaService := &PDAReplicaService{}
+
+	// nothing listens on port 1, so every member is logged and skipped right away
+	replicaGroup := func(gid int) ReplicaGroup {
+		return ReplicaGroup{Gid: gid, GroupName: "test", PdaGroupMembers: []string{"http://127.0.0.1:1"}, PdaCode: gid}
+	}
+
+	var created sync.WaitGroup
+	var mutex sync.Mutex
+	succeeded := 0
+	for i := 0; i < 16; i++ {
+		created.Add(1)
+		go func(i int) {
+			defer created.Done()
+			// every gid is created twice, only one of them may succeed
+			if _, err := replicaService.createReplicaGroup(i%8+1, replicaGroup(i%8+1)); err == nil {
+				mutex.Lock()
+				succeeded++
+				mutex.Unlock()
+			}
+			replicaService.getAvailableReplicaGroups()
+		}(i)
+	}
+	created.Wait()
+	if groups := replicaService.getAvailableReplicaGroups(); succeeded != 8 || len(groups) != 8 {
+		t.Fatalf("%d creates succeeded and %d groups are available, want 8 of each", succeeded, len(groups))
+	}
+
+	var deleted sync.WaitGroup
+	for gid := 1; gid <= 8; gid++ {
+		deleted.Add(1)
+		go func(gid int) {
+			defer deleted.Done()
+			if err := replicaService.deleteReplicaGroup(gid); err != nil {
+				t.Error(err)
+			}
+			if _, err := replicaService.getMembersFromReplicaGroup(gid); err == nil {
+				t.Errorf("replica group %d is still found after deleting it", gid)
+			}
+		}(gid)
+	}
+	deleted.Wait()
+	if groups := replicaService.getAvailableReplicaGroups(); len(groups) != 0 {
+		t.Fatalf("%d groups left after deleting all of them", len(groups))
+	}
+}
@@ -0,0 +1,758 @@
+/**
+Package server serves PDAs of the pda package over a REST API, each client session holding its own PDAs.
+*/
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+	"github.com/pravin-gayal/pda-processor/pda"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var pdaService *PDAService
+
+/*
+Method to return all the available pda in the system
+*/
+func pdaList(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, pdaService.getAllAvailablePDAs())
+}
+
+/*
+Method to list the open sessions with their last activity, only for administrators presenting the admin token as they
+reveal the session ids
+*/
+func sessionList(w http.ResponseWriter, r *http.Request) {
+	if len(adminToken) == 0 {
+		respondWithError(w, http.StatusForbidden, "session listing is disabled, no admin token is configured")
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("admin-token")), []byte(adminToken)) != 1 {
+		respondWithError(w, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, pdaService.listSessions())
+}
+
+/*
+Method to create new pda in the system
+*/
+func createPDA(w http.ResponseWriter, r *http.Request) {
+	//Accept PDA with ID and specification
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	// unmarshal body
+	var pdaProcessor pda.PDAProcessor
+	if err := json.NewDecoder(r.Body).Decode(&pdaProcessor); err != nil {
+		// the decode error comes first, followed by the problems of whatever part of the specification was read
+		validationError := &pda.ValidationError{Diagnostics: append([]pda.Diagnostic{pda.DecodeDiagnostic(err)}, pdaProcessor.Validate()...)}
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{"error": validationError.Error(), "diagnostics": validationError.Diagnostics})
+		return
+	}
+	pdaProcessor.ID = id
+
+	// create pda processor
+	createdPDA, err1 := pdaService.createNewPDA(id, pdaProcessor)
+	var validationError *pda.ValidationError
+	if errors.As(err1, &validationError) {
+		// return every problem found in the specification
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err1.Error(), "diagnostics": validationError.Diagnostics})
+		return
+	} else if err1 != nil {
+		// a PDA running into a limit before reading any input also reports which limit was hit
+		respondWithEvaluationError(w, err1)
+		return
+	}
+
+	// return created pda processor
+	respondWithJSON(w, http.StatusOK, createdPDA)
+}
+
+/*
+Method to create new pda in the system from a context-free grammar
+*/
+func createPDAFromGrammar(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	// unmarshal body
+	var grammar pda.Grammar
+	if err := json.NewDecoder(r.Body).Decode(&grammar); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid grammar: "+err.Error())
+		return
+	}
+
+	// compile grammar and create pda processor
+	createdPDA, err1 := pdaService.createPDAFromGrammar(id, grammar)
+	var validationError *pda.ValidationError
+	if errors.As(err1, &validationError) {
+		// return every problem found in the grammar
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err1.Error(), "diagnostics": validationError.Diagnostics})
+		return
+	} else if err1 != nil {
+		// a PDA running into a limit before reading any input also reports which limit was hit
+		respondWithEvaluationError(w, err1)
+		return
+	}
+
+	// return created pda processor
+	respondWithJSON(w, http.StatusOK, createdPDA)
+}
+
+func resetPDA(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// reset pda
+	err = pdaService.resetPDA(sessionId, pdaId)
+	if err != nil {
+		respondWithEvaluationError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]bool{"reset": true})
+}
+
+func isAccepted(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	isAccepted, err := pdaService.isAccepted(sessionId, pdaId)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]bool{"is_accepted": isAccepted})
+}
+
+func peek(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	k, err1 := strconv.Atoi(parseRequestVariable(r, "k"))
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, "k is not an integer")
+		return
+	}
+
+	states, err2 := pdaService.peek(sessionId, pdaId, k)
+	if err2 != nil {
+		respondWithError(w, http.StatusBadRequest, err2.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, states)
+}
+
+func stackLength(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	length, err1 := pdaService.stackLength(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]int{"stack_length": length})
+}
+
+func currentState(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	state, err1 := pdaService.currentState(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"current_state": state})
+}
+
+func output(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	symbols, separator, err1 := pdaService.output(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	// the separator of the request, when given, takes precedence over the one of the specification
+	if r.URL.Query().Has("separator") {
+		separator = r.URL.Query().Get("separator")
+	}
+	respondWithJSON(w, http.StatusOK, map[string]interface{}{"output": strings.Join(symbols, separator), "symbols": symbols})
+}
+
+func closePDA(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err = pdaService.closePDA(sessionId, pdaId)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]bool{"closed": true})
+}
+
+func deletePDA(w http.ResponseWriter, r *http.Request) {
+	pdaId, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "id is not an integer")
+		return
+	}
+
+	err = pdaService.deletePDA(pdaId)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]bool{"deleted": true})
+}
+
+func presentToken(w http.ResponseWriter, r *http.Request) {
+	// get session id and PDA id from header and path variables respectively
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// get position from the path variables
+	position, err := strconv.Atoi(parseRequestVariable(r, "position"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "position is not an integer")
+		return
+	}
+
+	// get token from the path variables
+	token := parseRequestVariable(r, "token")
+
+	isConsumed, err := pdaService.presentToken(sessionId, pdaId, token, position)
+	if err != nil {
+		respondWithEvaluationError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string]bool{"is_consumed": isConsumed})
+}
+
+func queuedTokens(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	pendingQueue, err1 := pdaService.getPendingQueue(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	if pendingQueue == nil {
+		pendingQueue = make([]string, 0)
+	}
+	respondWithJSON(w, http.StatusOK, pendingQueue)
+}
+
+func presentEOS(w http.ResponseWriter, r *http.Request) {
+	// get session id and PDA id from header and path variables respectively
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// get position from the path variables
+	position, err := strconv.Atoi(parseRequestVariable(r, "position"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "position is not an integer")
+		return
+	}
+
+	err = pdaService.presentEOS(sessionId, pdaId, position)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]bool{"eos_declared": true})
+}
+
+func snapshot(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	k, err1 := strconv.Atoi(parseRequestVariable(r, "k"))
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, "k is not an integer")
+		return
+	}
+
+	snapshot, err2 := pdaService.snapshot(sessionId, pdaId, k)
+	if err2 != nil {
+		respondWithError(w, http.StatusBadRequest, err2.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, snapshot)
+}
+
+func getC3State(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	session, err2 := pdaService.getSessionPDA(sessionId, pdaId)
+	if err2 != nil {
+		respondWithError(w, http.StatusBadRequest, err2.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, session)
+}
+
+func createSession(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	pdaId, err := strconv.Atoi(params["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	sessionId, err := pdaService.createSession(pdaId)
+	if err != nil {
+		respondWithSessionError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"sessionId": sessionId})
+}
+
+func loadPdaIntoAvailablePdas(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	pdaId, err := strconv.Atoi(params["id"])
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+	pdaService.loadPdaIntoAvailablePdas(pdaId)
+	respondWithJSON(w, http.StatusOK, map[string]string{})
+}
+
+func getPDAById(w http.ResponseWriter, r *http.Request) {
+	//Accept PDA with ID and specification
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	pdaProcessor, err := pdaService.getPDAById(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, pdaProcessor)
+}
+
+func trace(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	steps, err1 := pdaService.trace(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, steps)
+}
+
+func analyzeDeterminism(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	report, err := pdaService.analyzeDeterminism(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+func exportSession(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snapshot, err1 := pdaService.exportSession(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, snapshot)
+}
+
+func importSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	// unmarshal body
+	var snapshot pda.SessionSnapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		respondWithError(w, http.StatusBadRequest, "invalid session snapshot: "+err.Error())
+		return
+	}
+
+	sessionId, err1 := pdaService.importSession(id, snapshot)
+	if err1 != nil {
+		respondWithSessionError(w, err1)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]string{"sessionId": sessionId})
+}
+
+/*
+Method to retract consumed tokens, either ?steps=n for the last n tokens or ?position=p for every token from position p on
+*/
+func rollback(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	steps, position, byPosition := 1, 0, false
+	if value := r.URL.Query().Get("position"); value != "" {
+		position, err = strconv.Atoi(value)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "position is not an integer")
+			return
+		}
+		byPosition = true
+	} else if value := r.URL.Query().Get("steps"); value != "" {
+		steps, err = strconv.Atoi(value)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "steps is not an integer")
+			return
+		}
+	}
+
+	state, err1 := pdaService.rollback(sessionId, pdaId, steps, position, byPosition)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, state)
+}
+
+func checkpoints(w http.ResponseWriter, r *http.Request) {
+	sessionId, pdaId, err := parseSessionIdAndPdaId(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	positions, err1 := pdaService.checkpoints(sessionId, pdaId)
+	if err1 != nil {
+		respondWithError(w, http.StatusBadRequest, err1.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, positions)
+}
+
+func pdaGrammar(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	grammar, err := pdaService.grammar(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, grammar)
+}
+
+/*
+Method to list accepted inputs of the pda, ?max_len= bounds their number of tokens and ?limit= how many are returned
+*/
+func examples(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	maxLength, limit := pda.PDA_EXAMPLES_MAX_LENGTH, pda.PDA_EXAMPLES_LIMIT
+	if value := r.URL.Query().Get("max_len"); value != "" {
+		maxLength, err = strconv.Atoi(value)
+		if err != nil || maxLength < 0 {
+			respondWithError(w, http.StatusBadRequest, "max_len should be a non negative integer")
+			return
+		}
+	}
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			respondWithError(w, http.StatusBadRequest, "limit should be a positive integer")
+			return
+		}
+	}
+
+	report, err := pdaService.examples(id, maxLength, limit)
+	if err != nil {
+		respondWithEvaluationError(w, err)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+func emptiness(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	report, err := pdaService.emptiness(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, report)
+}
+
+/*
+Method to return the simplified specification of the pda with a report of the states and transitions removed
+*/
+func optimize(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	optimized, err := pdaService.optimize(id)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondWithJSON(w, http.StatusOK, optimized)
+}
+
+/*
+Method to render the state diagram of the pda as ?format=dot (default) or mermaid, the session-id header is optional and
+highlights the path the session has taken
+*/
+func graph(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "ID is not an integer")
+		return
+	}
+
+	diagram, err := pdaService.graph(r.Header.Get("session-id"), id, r.URL.Query().Get("format"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(diagram))
+}
+
+func handleRequests(portNumber string) {
+	if len(portNumber) == 0 {
+		portNumber = "8801"
+		pda.DefaultLogger().Info("no port specified, using default port", "port", portNumber)
+	}
+	myRouter := mux.NewRouter().StrictSlash(true)
+	myRouter.HandleFunc("/pdas", pdaList).Methods("GET")
+	myRouter.HandleFunc("/sessions", sessionList).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}", createPDA).Methods("PUT")
+	myRouter.HandleFunc("/pdas/{id}/reset", resetPDA).Methods("PUT")
+	myRouter.HandleFunc("/pdas/{id}/{token}/{position}", presentToken).Methods("PUT")
+	myRouter.HandleFunc("/pdas/{id}/eos/{position}", presentEOS).Methods("POST") // In requirement document this is PUT but it is conflicting with  "/pdas/{id}/{token}/{position}" so changed it to POST
+	myRouter.HandleFunc("/pdas/{id}/is_accepted", isAccepted).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/stack/top/{k}", peek).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/stack/len", stackLength).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/state", currentState).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/output", output).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/close", closePDA).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/delete", deletePDA).Methods("DELETE")
+	myRouter.HandleFunc("/pdas/{id}/tokens", queuedTokens).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/snapshot/{k}", snapshot).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/join", addPDAToReplicaGroup).Methods("PUT")
+	myRouter.HandleFunc("/pdas/{id}/code", getPDAById).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/c3state", getC3State).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/determinism", analyzeDeterminism).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/grammar", pdaGrammar).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/examples", examples).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/emptiness", emptiness).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/optimize", optimize).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/graph", graph).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/trace", trace).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/export", exportSession).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/import", importSession).Methods("PUT")
+	myRouter.HandleFunc("/pdas/{id}/rollback", rollback).Methods("PUT")
+	myRouter.HandleFunc("/pdas/{id}/checkpoints", checkpoints).Methods("GET")
+
+	// additional utilities apis
+	myRouter.HandleFunc("/pdas/{id}/createSession", createSession).Methods("GET")
+	myRouter.HandleFunc("/pdas/{id}/load", loadPdaIntoAvailablePdas).Methods("GET")
+
+	// grammar APIs
+	myRouter.HandleFunc("/grammars/{id}", createPDAFromGrammar).Methods("PUT")
+
+	// replica group APIs
+	myRouter.HandleFunc("/replica_pdas", getAvailableReplicaGroups).Methods("GET")
+	myRouter.HandleFunc("/replica_pdas/{gid}", createReplicaGroup).Methods("PUT")
+	myRouter.HandleFunc("/replica_pdas/{gid}/reset", resetReplicaGroup).Methods("PUT")
+	myRouter.HandleFunc("/replica_pdas/{gid}/members", getMembersFromReplicaGroup).Methods("GET")
+	myRouter.HandleFunc("/replica_pdas/{gid}/connect", connectToReplicaGroup).Methods("GET")
+	myRouter.HandleFunc("/replica_pdas/{gid}/close", closeReplicaGroup).Methods("PUT")
+	myRouter.HandleFunc("/replica_pdas/{gid}/delete", deleteReplicaGroup).Methods("DELETE")
+
+	// TODO To be implemented
+	// TODO myRouter.HandleFunc("/replica_pdas/{id}/code", getPDASpecs).Methods("GET")
+
+	pda.DefaultLogger().Info("starting PDA server", "port", portNumber, "base_url", "http://localhost:"+portNumber+"/")
+	err := http.ListenAndServe(":"+portNumber,
+		handlers.CORS(
+			handlers.AllowedHeaders([]string{"X-Requested-With", "Content-Type", "Authorization", "session-id", "admin-token"}),
+			handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "HEAD", "OPTIONS"}),
+			handlers.AllowedOrigins([]string{"*"}))(myRouter))
+	pda.DefaultLogger().Error("PDA server stopped", "port", portNumber, "error", err)
+	os.Exit(1)
+}
+
+func parseSessionIdAndPdaId(r *http.Request) (string, int, error) {
+	sessionId := r.Header.Get("session-id")
+	if len(sessionId) == 0 {
+		return sessionId, 0, errors.New("missing session id")
+	}
+
+	pdaId, err := strconv.Atoi(parseRequestVariable(r, "id"))
+	if err != nil {
+		return sessionId, pdaId, errors.New("id is not an integer")
+	}
+	if pdaId < 0 {
+		return sessionId, pdaId, errors.New("id should be a positive integer")
+	}
+
+	return sessionId, pdaId, nil
+}
+func parseRequestVariable(r *http.Request, paramKey string) string {
+	params := mux.Vars(r)
+	return params[paramKey]
+}
+func respondWithError(w http.ResponseWriter, code int, message string) {
+	respondWithJSON(w, code, map[string]string{"error": message})
+}
+
+/*
+respond with the error of an evaluation, a PDA running into one of its limits also reports which limit was hit
+*/
+func respondWithEvaluationError(w http.ResponseWriter, err error) {
+	var limitError *pda.LimitError
+	if errors.As(err, &limitError) {
+		respondWithJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error(), "limit": limitError})
+		return
+	}
+	respondWithError(w, http.StatusBadRequest, err.Error())
+}
+
+/*
+respond with the error of a session creation, the server having no room for another session is not the client's fault
+*/
+func respondWithSessionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errSessionLimitReached) {
+		respondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	respondWithError(w, http.StatusBadRequest, err.Error())
+}
+
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	response, _ := json.Marshal(payload)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(response)
+}
+
+/**
+token administrators present in the admin-token header, admin requests are refused while it is empty.
+*/
+var adminToken string
+
+/**
+ConfigureAdminToken sets the token administrators have to present to list the sessions, as read from PDA_ADMIN_TOKEN.
+The session listing is disabled while the token is empty. It has to be called before Run.
+*/
+func ConfigureAdminToken(token string) {
+	adminToken = token
+}
+
+/**
+Run starts the PDA service and serves the REST API on the given port until the server stops, the port defaults to
+8801 when empty.
+*/
+func Run(port string) {
+	// init service
+	pdaService = &PDAService{}
+
+	pdaService.initService()
+
+	// register handler to router
+	handleRequests(port)
+}
@@ -0,0 +1,41 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pravin-gayal/pda-processor/pda"
+)
+
+func TestCreatePDAReportsDecodeErrorFirst(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		body string
+		path string
+	}{
+		{"wrong type", `{"name": "test", "states": "q0", "eos": "$"}`, "states"},
+		{"no JSON", `{"name": "test",`, ""},
+	} {
+		request := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/pdas/1", strings.NewReader(test.body)), map[string]string{"id": "1"})
+		recorder := httptest.NewRecorder()
+		createPDA(recorder, request)
+
+		var response struct {
+			Error       string           `json:"error"`
+			Diagnostics []pda.Diagnostic `json:"diagnostics"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if recorder.Code != http.StatusBadRequest || len(response.Diagnostics) < 2 {
+			t.Fatalf("%s: got %d with %+v, want 400 with the decode error and the validation diagnostics", test.name, recorder.Code, response)
+		}
+		if first := response.Diagnostics[0]; first.Code != pda.PDA_DIAGNOSTIC_INVALID_JSON || first.Path != test.path {
+			t.Errorf("%s: got first diagnostic %+v, want invalid_json at %q", test.name, first, test.path)
+		}
+	}
+}
@@ -0,0 +1,656 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pravin-gayal/pda-processor/pda"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+type PDAService struct {
+	pdas     *PDARegistry
+	sessions *SessionRegistry
+}
+
+/**
+PDA as returned on creation, the specification together with the emptiness check of its language.
+*/
+type CreatedPDA struct {
+	pda.PDAProcessor
+	Language pda.EmptinessReport `json:"language"`
+}
+
+/**
+simplified specification together with the report of what was removed from it.
+*/
+type OptimizedPDA struct {
+	Specification pda.PDAProcessor       `json:"specification"`
+	Report        pda.OptimizationReport `json:"report"`
+}
+
+func (pdaService *PDAService) getAllAvailablePDAs() []pda.PDAProcessor {
+	return pdaService.pdas.list()
+}
+
+func (pdaService *PDAService) createNewPDA(id int, pdaProcessor pda.PDAProcessor) (CreatedPDA, error) {
+	pda.DefaultLogger().Debug("creating PDA", "pda_id", id)
+	pdaProcessor.ID = id
+	isValid, err := pdaService.validatePDAId(id)
+	if !isValid && err != nil {
+		return CreatedPDA{PDAProcessor: pdaProcessor}, err
+	}
+
+	// validate pda details and put it at its start state like every session will, a PDA which can not get there is not stored
+	err = pdaProcessor.Init()
+	if err != nil {
+		var limitError *pda.LimitError
+		if errors.As(err, &limitError) {
+			return CreatedPDA{PDAProcessor: pdaProcessor}, fmt.Errorf("PDA can not be put at its start state, %w", err)
+		}
+		return CreatedPDA{PDAProcessor: pdaProcessor}, err
+	}
+
+	// report a specification accepting nothing right away, it is a common mistake. It is checked before the PDA becomes
+	// visible and within a smaller grammar than on request, so that uploads can not pin the server
+	language := pdaProcessor.CheckEmptinessWithin(PDA_UPLOAD_GRAMMAR_MAX_PRODUCTIONS)
+	if language.Empty {
+		pda.DefaultLogger().Warn("PDA accepts no input", "pda_id", id)
+	}
+
+	// add to available pdas, the json file for newly incoming specification is created while the id is held
+	err = pdaService.pdas.add(pdaProcessor, func() error {
+		_, err := createJsonFile(id, pdaProcessor)
+		return err
+	})
+	if err != nil {
+		return CreatedPDA{PDAProcessor: pdaProcessor}, err
+	}
+
+	pda.DefaultLogger().Info("created PDA", "pda_id", id, "name", pdaProcessor.Name)
+	// return created PDA object
+	return CreatedPDA{PDAProcessor: pdaProcessor, Language: language}, nil
+}
+
+/**
+Method to compile a context-free grammar into a PDA and create it with given id
+*/
+func (pdaService *PDAService) createPDAFromGrammar(id int, grammar pda.Grammar) (CreatedPDA, error) {
+	pdaProcessor, err := grammar.ToPDA(id)
+	if err != nil {
+		return CreatedPDA{PDAProcessor: pdaProcessor}, err
+	}
+	return pdaService.createNewPDA(id, pdaProcessor)
+}
+
+func (pdaService *PDAService) initService() {
+	// initialize PDA and session registries
+	pdaService.pdas = newPDARegistry()
+	pdaService.sessions = newSessionRegistry(sessionLimit, sessionIdleTTL, sessionAbsoluteTTL)
+
+	// remove expired sessions in the background, requests to an expired session also remove it
+	if sessionIdleTTL > 0 || sessionAbsoluteTTL > 0 {
+		go pdaService.sessions.reap(PDA_SESSION_REAP_INTERVAL)
+	}
+
+	// read existing PDAs from the file system and load them in available PDA list
+	pdaService.loadExistingPDAs()
+}
+
+func (pdaService *PDAService) loadExistingPDAs() {
+	files, err := ioutil.ReadDir(PDA_FILES_BASE_FOLDER)
+	if err != nil {
+		pda.DefaultLogger().Error("could not read PDA specifications", "folder", PDA_FILES_BASE_FOLDER, "error", err)
+		os.Exit(1)
+	}
+	for _, f := range files {
+		pdaProcessor := openFileByName(f.Name())
+		if pdaProcessor != nil {
+			pdaService.addLoadedPDA(*pdaProcessor)
+		}
+	}
+}
+
+func (pdaService *PDAService) createSession(pdaId int) (string, error) {
+	// PDAs are stored at their start state and handed out as copies, so the session owns a fresh PDA
+	pdaProcessor, exists := pdaService.pdas.get(pdaId)
+	if !exists {
+		pda.DefaultLogger().Debug("PDA does not exist", "pda_id", pdaId)
+		return "", errors.New("PDA with specified id does not exist")
+	}
+
+	// the session is bound before it is added, a request to it may come in as soon as it is
+	var sessionId string
+	for added := false; !added; {
+		var err error
+		sessionId, err = newSessionId()
+		if err != nil {
+			pda.DefaultLogger().Error("could not generate session id", "error", err)
+			return "", errors.New("couldn't create session")
+		}
+		pdaProcessor.BindSession(sessionId)
+		added, err = pdaService.sessions.add(sessionId, &pdaProcessor)
+		if err != nil {
+			pda.DefaultLogger().Warn("session not created", "pda_id", pdaId, "error", err)
+			return "", err
+		}
+	}
+
+	// the PDA may have been deleted meanwhile, its sessions are gone then
+	if _, exists := pdaService.pdas.get(pdaId); !exists {
+		pdaService.sessions.remove(sessionId)
+		return "", errors.New("PDA with specified id does not exist")
+	}
+	pdaProcessor.Logger().Info("created session")
+	return sessionId, nil
+}
+
+/**
+Method to reset PDA in given session id
+*/
+func (pdaService *PDAService) resetPDA(sessionId string, pdaId int) error {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// reset pda
+	return pdaProcessor.Reset()
+}
+
+/**
+Method to check if PDA is in accepted state
+*/
+func (pdaService *PDAService) isAccepted(sessionId string, pdaId int) (bool, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	// return is accepted boolean
+	isAccepted := pdaProcessor.IsAccepted()
+	return isAccepted, nil
+}
+
+/**
+Method to peek "k" states from the stack
+*/
+func (pdaService *PDAService) peek(sessionId string, pdaId int, k int) ([]string, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	//  return array of states
+	states := pdaProcessor.Peek(k)
+
+	// return empty list if stack is null
+	if states == nil {
+		states = make([]string, 0)
+	}
+
+	return states, nil
+}
+
+func (pdaService *PDAService) stackLength(sessionId string, pdaId int) (int, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	length := 0
+	for _, s := range pdaProcessor.Stack.Symbols() {
+		if s != pdaProcessor.Eos {
+			length = length + 1
+		}
+	}
+
+	// return stack length
+	return length, nil
+}
+
+func (pdaService *PDAService) currentState(sessionId string, pdaId int) (string, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	// return current state
+	return pdaProcessor.CurrentState, nil
+}
+
+/**
+return the output symbols written so far together with the output separator of the specification.
+*/
+func (pdaService *PDAService) output(sessionId string, pdaId int) ([]string, string, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	// return output written so far
+	return pdaProcessor.OutputSymbols(), pdaProcessor.OutputSeparator, nil
+}
+
+func (pdaService *PDAService) closePDA(sessionId string, pdaId int) error {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return err
+	}
+
+	// call close, then release the session so that its PDA can be garbage collected
+	pdaProcessor.Close()
+	release()
+	pdaService.sessions.remove(sessionId)
+	return nil
+}
+
+/**
+Method to list the open sessions with their last activity, for administrators
+*/
+func (pdaService *PDAService) listSessions() []SessionInfo {
+	return pdaService.sessions.list()
+}
+
+func (pdaService *PDAService) deletePDA(pdaId int) error {
+	// remove PDA processor object from available PDAs together with its spec file on the file system
+	filename := PDA_FILE_NAME_PREFIX + strconv.Itoa(pdaId) + PDA_FILE_NAME_POSTFIX
+	existed, err := pdaService.pdas.remove(pdaId, func() error {
+		return os.Remove(filepath.Join(PDA_FILES_BASE_FOLDER, filepath.Base(filename)))
+	})
+	if !existed {
+		pda.DefaultLogger().Debug("PDA does not exist", "pda_id", pdaId)
+		return errors.New("PDA with specified id does not exist")
+	}
+	if err != nil {
+		pda.DefaultLogger().Error("failed to delete PDA specification file", "pda_id", pdaId, "file", filename, "error", err)
+		return errors.New("failed to delete file from the file system")
+	}
+
+	// invalidate all the sessions using this PDA
+	count := pdaService.sessions.removeByPDA(pdaId)
+	pda.DefaultLogger().Info("deleted PDA", "pda_id", pdaId, "sessions", count)
+
+	return nil
+}
+
+func (pdaService *PDAService) presentToken(sessionId string, pdaId int, token string, position int) (bool, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	// check if token is valid input alphabet
+	found := false
+	for _, inputAlphabet := range pdaProcessor.InputAlphabet {
+		if inputAlphabet == token {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, errors.New("input token is not supported by PDA")
+	}
+
+	// present token to PDA
+	transitionTaken, err := pdaProcessor.Put(position, token)
+	if err != nil {
+		pdaProcessor.Logger().Warn("token rejected", "token", token, "position", position, "error", err)
+		return false, err
+	}
+
+	return len(transitionTaken) != 0, nil
+}
+
+func (pdaService *PDAService) getPendingQueue(sessionId string, pdaId int) ([]string, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// return stack length
+	return pdaProcessor.QueuedTokens(), nil
+}
+
+func (pdaService *PDAService) getSessionPDA(sessionId string, pdaId int) (interface{}, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	m := make(map[string]interface{})
+
+	m["session_id"] = sessionId
+	m["pda_id"] = pdaProcessor.ID
+	m["pda_name"] = pdaProcessor.Name
+	m["pda_stack"] = pdaProcessor.Stack.Symbols()
+
+	return m, nil
+}
+
+func (pdaService *PDAService) presentEOS(sessionId string, pdaId int, position int) error {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// present token to PDA
+	err = pdaProcessor.EOS(position)
+	if err != nil {
+		pdaProcessor.Logger().Warn("EOS rejected", "position", position, "error", err)
+		return err
+	}
+	return nil
+}
+
+type result struct {
+	CurrentState string   `json:"current_state"`
+	Peek         []string `json:"peek"`
+	QueuedTokens []string `json:"queued_tokens"`
+}
+
+func (pdaService *PDAService) snapshot(sessionId string, pdaId int, k int) (result, error) {
+	result := result{}
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return result, err
+	}
+	defer release()
+
+	peek := pdaProcessor.Peek(k)
+	if peek == nil {
+		peek = make([]string, 0)
+	}
+
+	queue := pdaProcessor.QueuedTokens()
+	if queue == nil {
+		queue = make([]string, 0)
+	}
+
+	result.CurrentState = pdaProcessor.State()
+	result.Peek = peek
+	result.QueuedTokens = queue
+	return result, nil
+}
+
+func (pdaService *PDAService) loadPdaIntoAvailablePdas(pdaId int) {
+	filename := PDA_FILE_NAME_PREFIX + strconv.Itoa(pdaId) + PDA_FILE_NAME_POSTFIX
+	pdaProcessor := openFileByName(filename)
+	if pdaProcessor != nil {
+		pdaService.addLoadedPDA(*pdaProcessor)
+	}
+}
+
+func (pdaService *PDAService) getPDAById(pdaId int) (pda.PDAProcessor, error) {
+	pdaProcessor, exists := pdaService.pdas.get(pdaId)
+	if !exists {
+		return pdaProcessor, errors.New("Invalid pda id")
+	}
+
+	return pdaProcessor, nil
+}
+
+/**
+Method to return the moves taken by the PDA in given session
+*/
+func (pdaService *PDAService) trace(sessionId string, pdaId int) ([]pda.TraceStep, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return pdaProcessor.TransitionsTaken(), nil
+}
+
+/**
+Method to report transitions which make the PDA nondeterministic
+*/
+func (pdaService *PDAService) analyzeDeterminism(pdaId int) (pda.DeterminismReport, error) {
+	pdaProcessor, err := pdaService.getPDAById(pdaId)
+	if err != nil {
+		return pda.DeterminismReport{}, err
+	}
+
+	return pdaProcessor.AnalyzeDeterminism(), nil
+}
+
+/**
+Method to export the runtime state of the PDA in given session as a versioned snapshot
+*/
+func (pdaService *PDAService) exportSession(sessionId string, pdaId int) (pda.SessionSnapshot, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return pda.SessionSnapshot{}, err
+	}
+	defer release()
+
+	return pdaProcessor.ExportSnapshot(), nil
+}
+
+/**
+Method to restore a snapshot into a new session of the PDA, returns the id of the new session
+*/
+func (pdaService *PDAService) importSession(pdaId int, snapshot pda.SessionSnapshot) (string, error) {
+	sessionId, err := pdaService.createSession(pdaId)
+	if err != nil {
+		return "", err
+	}
+
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return "", err
+	}
+
+	err = pdaProcessor.ImportSnapshot(snapshot)
+	if err == nil {
+		pdaProcessor.Logger().Info("restored session snapshot", "version", snapshot.Version)
+	}
+	release()
+	if err != nil {
+		pdaService.sessions.remove(sessionId)
+		return "", err
+	}
+	return sessionId, nil
+}
+
+/**
+Method to retract consumed tokens of the PDA in given session, either the last steps tokens or every token from position on
+*/
+func (pdaService *PDAService) rollback(sessionId string, pdaId int, steps int, position int, byPosition bool) (interface{}, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if byPosition {
+		err = pdaProcessor.RollbackToPosition(position)
+	} else {
+		err = pdaProcessor.Rollback(steps)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	m["current_state"] = pdaProcessor.State()
+	m["pda_stack"] = pdaProcessor.Stack.Symbols()
+	m["queued_tokens"] = pdaProcessor.QueuedTokens()
+	return m, nil
+}
+
+/**
+Method to list the positions of the tokens which can be retracted in given session
+*/
+func (pdaService *PDAService) checkpoints(sessionId string, pdaId int) ([]int, error) {
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return pdaProcessor.CheckpointPositions(), nil
+}
+
+/**
+Method to convert the PDA into an equivalent context-free grammar
+*/
+func (pdaService *PDAService) grammar(pdaId int) (pda.Grammar, error) {
+	pdaProcessor, err := pdaService.getPDAById(pdaId)
+	if err != nil {
+		return pda.Grammar{}, err
+	}
+
+	return pdaProcessor.ToGrammar()
+}
+
+/**
+Method to list accepted inputs of the PDA, up to limit inputs of at most maxLength tokens
+*/
+func (pdaService *PDAService) examples(pdaId int, maxLength int, limit int) (pda.ExamplesReport, error) {
+	pdaProcessor, err := pdaService.getPDAById(pdaId)
+	if err != nil {
+		return pda.ExamplesReport{}, err
+	}
+
+	return pdaProcessor.Examples(maxLength, limit)
+}
+
+/**
+Method to tell whether the PDA accepts any input, with a shortest accepted input when it does
+*/
+func (pdaService *PDAService) emptiness(pdaId int) (pda.EmptinessReport, error) {
+	pdaProcessor, err := pdaService.getPDAById(pdaId)
+	if err != nil {
+		return pda.EmptinessReport{}, err
+	}
+
+	return pdaProcessor.CheckEmptiness(), nil
+}
+
+/**
+Method to simplify the PDA specification, the stored PDA is left as it is
+*/
+func (pdaService *PDAService) optimize(pdaId int) (OptimizedPDA, error) {
+	pdaProcessor, err := pdaService.getPDAById(pdaId)
+	if err != nil {
+		return OptimizedPDA{}, err
+	}
+
+	optimized, report, err := pdaProcessor.Optimize()
+	return OptimizedPDA{Specification: optimized, Report: report}, err
+}
+
+/**
+Method to render the state diagram of the PDA, with the path taken by the session when a session id is given
+*/
+func (pdaService *PDAService) graph(sessionId string, pdaId int, format string) (string, error) {
+	if sessionId == "" {
+		pdaProcessor, err := pdaService.getPDAById(pdaId)
+		if err != nil {
+			return "", err
+		}
+		return pdaProcessor.RenderGraph(format, nil)
+	}
+
+	// get pda for session id
+	pdaProcessor, release, err := pdaService.sessions.acquire(sessionId, pdaId)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	return pdaProcessor.RenderGraph(format, pdaProcessor.TransitionsTaken())
+}
+
+// ***************************************************************//
+// ******************** Private Methods **************************//
+// ***************************************************************//
+/**
+generate a session id from PDA_SESSION_ID_BYTES cryptographically random bytes, so that session ids can not be guessed.
+*/
+func newSessionId() (string, error) {
+	randomBytes := make([]byte, PDA_SESSION_ID_BYTES)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return "session_" + hex.EncodeToString(randomBytes), nil
+}
+
+func (pdaService *PDAService) addLoadedPDA(pdaProcessor pda.PDAProcessor) {
+	err := pdaService.pdas.add(pdaProcessor, nil)
+	if err != nil {
+		pda.DefaultLogger().Warn("PDA not loaded", "pda_id", pdaProcessor.ID, "error", err)
+	}
+}
+
+func (pdaService *PDAService) validatePDAId(id int) (bool, error) {
+	// validate id, it is checked again when the PDA is added
+	if id <= 0 {
+		return false, errors.New("ID should be a positive integer")
+	}
+	if _, exists := pdaService.pdas.get(id); exists {
+		return false, errors.New("ID already used")
+	}
+	return true, nil
+}
+
+func createJsonFile(pdaId int, pdaProcessor pda.PDAProcessor) (bool, error) {
+	dataBytes, err1 := json.MarshalIndent(pdaProcessor, "", "  ")
+	if err1 != nil {
+		return false, errors.New("could not marshal to a JSON file")
+	}
+	filename := PDA_FILE_NAME_PREFIX + strconv.Itoa(pdaId) + PDA_FILE_NAME_POSTFIX
+	err3 := ioutil.WriteFile(filepath.Join(PDA_FILES_BASE_FOLDER, filepath.Base(filename)), dataBytes, 0644)
+	if err3 != nil {
+		pda.DefaultLogger().Error("could not write PDA specification file", "pda_id", pdaId, "file", filename, "error", err3)
+		return false, errors.New("could not write a json to the file")
+	}
+	return true, nil
+}
+
+func openFileByName(fileName string) *pda.PDAProcessor {
+	filename := filepath.Join(PDA_FILES_BASE_FOLDER, fileName)
+	pdaProcessor := &pda.PDAProcessor{}
+	opened, err := pdaProcessor.Open(filename)
+	if err != nil {
+		pda.DefaultLogger().Error("could not open PDA specification", "file", filename, "error", err)
+		return nil
+	} else if !opened {
+		pda.DefaultLogger().Error("PDA specification file was not opened", "file", filename)
+		return nil
+	}
+	return pdaProcessor
+}
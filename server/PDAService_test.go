@@ -0,0 +1,184 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pravin-gayal/pda-processor/pda"
+)
+
+func TestCreateNewPDARejectsDivergingStart(t *testing.T) {
+	pdaService := &PDAService{pdas: newPDARegistry(), sessions: newSessionRegistry(0, 0, 0)}
+
+	// an epsilon self-loop pushing a symbol grows the stack forever before the first token
+	var pdaProcessor pda.PDAProcessor
+	err := json.Unmarshal([]byte(`{
+		"name": "diverging",
+		"states": ["q1"],
+		"input_alphabet": ["0"],
+		"stack_alphabet": ["0"],
+		"accepting_states": ["q1"],
+		"start_state": "q1",
+		"transitions": [["q1", null, null, "q1", "0"]],
+		"eos": "$"
+	}`), &pdaProcessor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pdaService.createNewPDA(5, pdaProcessor)
+	var limitError *pda.LimitError
+	if !errors.As(err, &limitError) {
+		t.Fatalf("got %v, want a LimitError", err)
+	}
+	if _, exists := pdaService.pdas.get(5); exists {
+		t.Fatal("PDA which can not be put at its start state was stored")
+	}
+}
+
+/**
+service with the 0^n1^n PDA of testPdaSpecs1.json as PDA 7, which is only kept in the registry and has no file of its own.
+*/
+func serviceWithPDA(t *testing.T, maxSessions int) *PDAService {
+	service := &PDAService{pdas: newPDARegistry(), sessions: newSessionRegistry(maxSessions, 0, 0)}
+	var pdaProcessor pda.PDAProcessor
+	if _, err := pdaProcessor.Open("../PDAFiles/testPdaSpecs1.json"); err != nil {
+		t.Fatal(err)
+	}
+	pdaProcessor.ID = 7
+	if err := service.pdas.add(pdaProcessor, nil); err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+func TestCreateSessionStartsFromTheRegistryCopy(t *testing.T) {
+	pdaService := serviceWithPDA(t, 0)
+	first, err := pdaService.createSession(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pdaService.presentToken(first, 7, "0", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// a token presented to one session changes neither the stored PDA nor a later session
+	second, err := pdaService.createSession(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := mustGet(t, pdaService.pdas, 7)
+	firstLength, err := pdaService.stackLength(first, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondLength, err := pdaService.stackLength(second, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firstLength == stored.Stack.Len() || secondLength != stored.Stack.Len() {
+		t.Errorf("got stacks of %d and %d symbols, want the second session to start with the %d of the stored PDA", firstLength, secondLength, stored.Stack.Len())
+	}
+	if _, err := pdaService.createSession(8); err == nil {
+		t.Error("session of a PDA which does not exist was created")
+	}
+}
+
+func TestSessionLimit(t *testing.T) {
+	pdaService = serviceWithPDA(t, 2)
+	defer func() { pdaService = nil }()
+
+	createSessionRequest := func() (int, string) {
+		request := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/pdas/7/createSession", nil), map[string]string{"id": "7"})
+		recorder := httptest.NewRecorder()
+		createSession(recorder, request)
+		var response map[string]string
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code, response["sessionId"] + response["error"]
+	}
+
+	var sessionIds []string
+	for i := 0; i < 2; i++ {
+		code, sessionId := createSessionRequest()
+		if code != http.StatusOK {
+			t.Fatalf("session %d: got %d %s, want 200", i, code, sessionId)
+		}
+		sessionIds = append(sessionIds, sessionId)
+	}
+	if code, message := createSessionRequest(); code != http.StatusServiceUnavailable || !strings.Contains(message, "session limit reached") {
+		t.Fatalf("got %d %s, want 503 once the limit is reached", code, message)
+	}
+
+	// closing a session makes room for another one
+	if err := pdaService.closePDA(sessionIds[0], 7); err != nil {
+		t.Fatal(err)
+	}
+	if code, message := createSessionRequest(); code != http.StatusOK {
+		t.Fatalf("got %d %s, want 200 after closing a session", code, message)
+	}
+}
+
+func TestSessionReaper(t *testing.T) {
+	registry := newSessionRegistry(0, 50*time.Millisecond, 0)
+	for _, sessionId := range []string{"idle", "active"} {
+		if added, err := registry.add(sessionId, &pda.PDAProcessor{ID: 1}); !added || err != nil {
+			t.Fatal(added, err)
+		}
+	}
+	go registry.reap(10 * time.Millisecond)
+
+	// the idle session is removed by the reaper while requests keep the active one alive
+	deadline := time.Now().Add(5 * time.Second)
+	for len(registry.list()) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got sessions %v, want the idle session to be reaped", registry.list())
+		}
+		_, release, err := registry.acquire("active", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if sessions := registry.list(); sessions[0].SessionId != "active" {
+		t.Fatalf("got sessions %v, want the active one to be kept", sessions)
+	}
+	if _, _, err := registry.acquire("idle", 1); err == nil {
+		t.Fatal("reaped session can still be acquired")
+	}
+
+	for len(registry.list()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("session left idle was not reaped")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSessionAbsoluteTTL(t *testing.T) {
+	registry := newSessionRegistry(0, 0, time.Hour)
+	if added, err := registry.add("session", &pda.PDAProcessor{ID: 1}); !added || err != nil {
+		t.Fatal(added, err)
+	}
+
+	// activity does not extend the absolute time to live
+	_, release, err := registry.acquire("session", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if removed := registry.removeExpired(time.Now()); len(removed) != 0 {
+		t.Fatalf("removed %v before the time to live passed", removed)
+	}
+	if removed := registry.removeExpired(time.Now().Add(time.Hour)); len(removed) != 1 || removed[0] != "session" {
+		t.Fatalf("removed %v, want the session once its time to live passed", removed)
+	}
+}